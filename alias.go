@@ -0,0 +1,272 @@
+package elastic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+)
+
+// one action of _aliases,all actions of one request succeed or fail together
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.3/indices-aliases.html
+type AliasAction struct {
+	action        string
+	indices       []string
+	aliases       []string
+	filter        Query
+	routing       string
+	indexRouting  string
+	searchRouting string
+	isWriteIndex  *bool
+}
+
+// add alias to the indices
+func NewAliasAddAction(alias string, indices ...string) *AliasAction {
+	return &AliasAction{action: "add", aliases: []string{alias}, indices: indices}
+}
+
+// remove alias from the indices
+func NewAliasRemoveAction(alias string, indices ...string) *AliasAction {
+	return &AliasAction{action: "remove", aliases: []string{alias}, indices: indices}
+}
+
+// delete the indices,it works like delete index but in the same atomic request
+func NewAliasRemoveIndexAction(indices ...string) *AliasAction {
+	return &AliasAction{action: "remove_index", indices: indices}
+}
+
+// the alias only sees the documents match query,if query is a *QueryBody,only its query part is used
+func (this *AliasAction) Filter(query Query) *AliasAction {
+	this.filter = query
+	return this
+}
+
+// set both index routing and search routing
+func (this *AliasAction) Routing(routing string) *AliasAction {
+	this.routing = routing
+	return this
+}
+
+func (this *AliasAction) IndexRouting(routing string) *AliasAction {
+	this.indexRouting = routing
+	return this
+}
+
+func (this *AliasAction) SearchRouting(routing string) *AliasAction {
+	this.searchRouting = routing
+	return this
+}
+
+// the writes to an alias pointing to many indices go to the write index
+func (this *AliasAction) IsWriteIndex(isWriteIndex bool) *AliasAction {
+	this.isWriteIndex = &isWriteIndex
+	return this
+}
+
+// return {"add":{"indices":["a"],"aliases":["b"],"filter":{...}}}
+func (this *AliasAction) BuildBody() (map[string]interface{}, error) {
+	if len(this.indices) == 0 {
+		return nil, errors.New("alias action must have index")
+	}
+	item := make(map[string]interface{})
+	item["indices"] = this.indices
+	if this.action == "remove_index" {
+		return map[string]interface{}{this.action: item}, nil
+	}
+	if len(this.aliases) == 0 || this.aliases[0] == "" {
+		return nil, errors.New("alias action must have alias")
+	}
+	item["aliases"] = this.aliases
+	if this.filter != nil {
+		filter, err := buildQueryMap(this.filter)
+		if err != nil {
+			return nil, err
+		}
+		if filter != nil {
+			item["filter"] = filter["query"]
+		}
+	}
+	if this.routing != "" {
+		item["routing"] = this.routing
+	}
+	if this.indexRouting != "" {
+		item["index_routing"] = this.indexRouting
+	}
+	if this.searchRouting != "" {
+		item["search_routing"] = this.searchRouting
+	}
+	if this.isWriteIndex != nil {
+		item["is_write_index"] = *this.isWriteIndex
+	}
+	return map[string]interface{}{this.action: item}, nil
+}
+
+type AliasInfo struct {
+	Filter        map[string]interface{} `json:"filter,omitempty"`
+	IndexRouting  string                 `json:"index_routing,omitempty"`
+	SearchRouting string                 `json:"search_routing,omitempty"`
+	IsWriteIndex  *bool                  `json:"is_write_index,omitempty"`
+}
+
+type IndexAliases struct {
+	Aliases map[string]*AliasInfo `json:"aliases"`
+}
+
+// index name to its aliases
+type AliasesResult map[string]*IndexAliases
+
+// the indices the alias points to,sorted by name
+func (this AliasesResult) IndicesOf(alias string) []string {
+	indices := make([]string, 0)
+	for index, aliases := range this {
+		if aliases == nil {
+			continue
+		}
+		if _, ok := aliases.Aliases[alias]; ok {
+			indices = append(indices, index)
+		}
+	}
+	sort.Strings(indices)
+	return indices
+}
+
+// the aliases of index,sorted by name
+func (this AliasesResult) AliasesOf(index string) []string {
+	names := make([]string, 0)
+	if aliases, ok := this[index]; ok && aliases != nil {
+		for name := range aliases.Aliases {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// do all actions in one request
+func (this *Client) UpdateAliases(actions ...*AliasAction) error {
+	return this.UpdateAliasesContext(context.Background(), actions...)
+}
+
+func (this *Client) UpdateAliasesContext(ctx context.Context, actions ...*AliasAction) error {
+	if len(actions) == 0 {
+		return errors.New("update aliases must have actions")
+	}
+	actionList := make([]map[string]interface{}, len(actions))
+	for i, action := range actions {
+		a, err := action.BuildBody()
+		if err != nil {
+			return err
+		}
+		actionList[i] = a
+	}
+	body, err := json.Marshal(map[string]interface{}{"actions": actionList})
+	if err != nil {
+		return err
+	}
+
+	response, err := this.performRequest(ctx, "POST", "/_aliases", body, "application/json")
+	if err != nil {
+		return err
+	}
+	result := new(AliasResult)
+
+	return this.decodeResponse(ctx, response, result)
+}
+
+func (this *Client) RemoveAlias(index string, names ...string) error {
+	return this.RemoveAliasContext(context.Background(), index, names...)
+}
+
+func (this *Client) RemoveAliasContext(ctx context.Context, index string, names ...string) error {
+	actions := make([]*AliasAction, len(names))
+	for i, name := range names {
+		actions[i] = NewAliasRemoveAction(name, index)
+	}
+	return this.UpdateAliasesContext(ctx, actions...)
+}
+
+// move alias from oldIndex to newIndex atomically
+func (this *Client) SwapAlias(alias string, oldIndex string, newIndex string) error {
+	return this.SwapAliasContext(context.Background(), alias, oldIndex, newIndex)
+}
+
+func (this *Client) SwapAliasContext(ctx context.Context, alias string, oldIndex string, newIndex string) error {
+	return this.UpdateAliasesContext(ctx, NewAliasRemoveAction(alias, oldIndex), NewAliasAddAction(alias, newIndex))
+}
+
+// get the aliases of index,index and names both can be wildcards,
+// index can be empty to look up in all indices,names can be empty to get all aliases
+// the missing names are skipped,the result is empty if none of them is found
+func (this *Client) GetAliases(index string, names ...string) (AliasesResult, error) {
+	return this.GetAliasesContext(context.Background(), index, names...)
+}
+
+func (this *Client) GetAliasesContext(ctx context.Context, index string, names ...string) (AliasesResult, error) {
+	path := buildPath(nil, index, "_alias")
+	if len(names) > 0 {
+		path = buildPath(nil, index, "_alias", joinNames(names))
+	}
+	response, err := this.performRequest(ctx, "GET", path, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	// when some of names are missing,elastic responses 404 with a plain string error
+	// beside the aliases found,like {"error":"alias [b] missing","status":404,"idx":{"aliases":{"a":{}}}}
+	raw := make(map[string]json.RawMessage)
+	err = this.decodeResponse(ctx, response, &raw)
+	if err != nil {
+		if e := AsElasticError(err); e == nil || e.Status != 404 || e.Type != "" {
+			return nil, err
+		}
+	}
+	result := make(AliasesResult)
+	for index, data := range raw {
+		// skip "error" and "status",an index is always an object
+		if (index == "error" || index == "status") && !strings.HasPrefix(string(data), "{") {
+			continue
+		}
+		aliases := new(IndexAliases)
+		if err := json.Unmarshal(data, aliases); err != nil {
+			return nil, err
+		}
+		result[index] = aliases
+	}
+	return result, nil
+}
+
+// get the concrete indices the alias points to
+func (this *Client) AliasIndices(alias string) ([]string, error) {
+	return this.AliasIndicesContext(context.Background(), alias)
+}
+
+func (this *Client) AliasIndicesContext(ctx context.Context, alias string) ([]string, error) {
+	result, err := this.GetAliasesContext(ctx, "", alias)
+	if err != nil {
+		return nil, err
+	}
+	return result.IndicesOf(alias), nil
+}
+
+// check if all the aliases exist
+func (this *Client) AliasExists(names ...string) (bool, error) {
+	return this.AliasExistsContext(context.Background(), names...)
+}
+
+func (this *Client) AliasExistsContext(ctx context.Context, names ...string) (bool, error) {
+	if len(names) == 0 {
+		return false, errors.New("alias exists must have alias")
+	}
+	response, err := this.performRequest(ctx, "HEAD", buildPath(nil, "_alias", joinNames(names)), nil, "")
+	if err != nil {
+		return false, err
+	}
+	err = this.decodeResponse(ctx, response, nil)
+	if err != nil {
+		if IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,299 @@
+package elastic
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	DEFAULT_BULK_WORKERS = 1
+	DEFAULT_BULK_ACTIONS = 1000
+	DEFAULT_BULK_SIZE    = 5 << 20
+)
+
+// collect the actions added by many goroutines and send them by Bulk in background,
+// the actions are sent when the count or the bytes of them reaches the limit,
+// or the flush interval is passed
+type BulkProcessor struct {
+	client        *Client
+	workers       int
+	bulkActions   int
+	bulkSize      int
+	flushInterval time.Duration
+	params        []string
+	retrier       *Retrier
+	before        func(id int64, actions []Action)
+	after         func(id int64, actions []Action, result *BulkResult, err error)
+
+	mu       sync.Mutex
+	actions  []Action
+	body     []byte
+	batchId  int64
+	inflight map[int64]*bulkBatch
+	started  bool
+	closed   bool
+	batches  chan *bulkBatch
+	stop     chan struct{}
+	wg       sync.WaitGroup
+}
+
+type bulkBatch struct {
+	id      int64
+	actions []Action
+	body    []byte
+	// closed when the batch is sent
+	done chan struct{}
+}
+
+func NewBulkProcessor(client *Client) *BulkProcessor {
+	this := &BulkProcessor{
+		client:      client,
+		workers:     DEFAULT_BULK_WORKERS,
+		bulkActions: DEFAULT_BULK_ACTIONS,
+		bulkSize:    DEFAULT_BULK_SIZE,
+		inflight:    make(map[int64]*bulkBatch),
+	}
+	return this
+}
+
+// the count of goroutines sending the batches at the same time
+func (this *BulkProcessor) Workers(workers int) *BulkProcessor {
+	this.workers = workers
+	return this
+}
+
+// send the batch when the count of actions reaches it,0 means no limit
+func (this *BulkProcessor) BulkActions(bulkActions int) *BulkProcessor {
+	this.bulkActions = bulkActions
+	return this
+}
+
+// send the batch when the bytes of request body reaches it,0 means no limit
+func (this *BulkProcessor) BulkSize(bulkSize int) *BulkProcessor {
+	this.bulkSize = bulkSize
+	return this
+}
+
+// send the pending actions on every interval,0 means never
+func (this *BulkProcessor) FlushInterval(interval time.Duration) *BulkProcessor {
+	this.flushInterval = interval
+	return this
+}
+
+// the params of every bulk request,like refresh,pipeline
+func (this *BulkProcessor) Params(params ...string) *BulkProcessor {
+	this.params = append(this.params, params...)
+	return this
+}
+
+// resend the items of a batch rejected with a retryable status by BulkWithRetry,
+// After gets the last result of every action then
+func (this *BulkProcessor) Retrier(retrier *Retrier) *BulkProcessor {
+	this.retrier = retrier
+	return this
+}
+
+// called before a batch is sent,id increases from 1 for every batch
+func (this *BulkProcessor) Before(before func(id int64, actions []Action)) *BulkProcessor {
+	this.before = before
+	return this
+}
+
+// called after a batch is sent,err is set when the whole request fails,
+// the failed items are in result.Failed() when the request succeeds
+// Add and Flush can be called in after,but Close can't,it waits for the after callbacks to return
+func (this *BulkProcessor) After(after func(id int64, actions []Action, result *BulkResult, err error)) *BulkProcessor {
+	this.after = after
+	return this
+}
+
+// start the workers,the processor can't be configured after it is started
+func (this *BulkProcessor) Start() error {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	if this.started {
+		return errors.New("bulk processor is already started")
+	}
+	if this.workers <= 0 {
+		return errors.New("bulk processor must have workers")
+	}
+	this.started = true
+	this.batches = make(chan *bulkBatch)
+	this.stop = make(chan struct{})
+	for i := 0; i < this.workers; i++ {
+		this.wg.Add(1)
+		go this.work()
+	}
+	if this.flushInterval > 0 {
+		this.wg.Add(1)
+		go this.flusher()
+	}
+	return nil
+}
+
+// add an action,it blocks when all the workers are busy and the batch is full,
+// an error is returned if the action is invalid or the processor is not running
+func (this *BulkProcessor) Add(action Action) error {
+	data, err := action.Format()
+	if err != nil {
+		return err
+	}
+
+	this.mu.Lock()
+	if !this.started || this.closed {
+		this.mu.Unlock()
+		return errors.New("bulk processor is not running")
+	}
+	this.actions = append(this.actions, action)
+	this.body = append(this.body, data...)
+	this.body = append(this.body, '\n')
+	var batch *bulkBatch
+	if (this.bulkActions > 0 && len(this.actions) >= this.bulkActions) ||
+		(this.bulkSize > 0 && len(this.body) >= this.bulkSize) {
+		batch = this.takeBatch()
+	}
+	this.mu.Unlock()
+
+	if batch != nil {
+		this.batches <- batch
+	}
+	return nil
+}
+
+// send the pending actions and wait until they and the batches taken before are sent,
+// the batches taken by the later Add are not waited,and the After callbacks may still be running
+func (this *BulkProcessor) Flush() error {
+	this.mu.Lock()
+	if !this.started || this.closed {
+		this.mu.Unlock()
+		return errors.New("bulk processor is not running")
+	}
+	batch := this.takeBatch()
+	waiting := this.inflightBatches()
+	this.mu.Unlock()
+
+	if batch != nil {
+		this.batches <- batch
+	}
+	waitBatches(waiting)
+	return nil
+}
+
+// stop accepting actions,send the pending ones and wait for the workers to exit,
+// don't call it in the After callback
+func (this *BulkProcessor) Close() error {
+	this.mu.Lock()
+	if !this.started || this.closed {
+		this.mu.Unlock()
+		return nil
+	}
+	this.closed = true
+	batch := this.takeBatch()
+	waiting := this.inflightBatches()
+	this.mu.Unlock()
+
+	if batch != nil {
+		this.batches <- batch
+	}
+	waitBatches(waiting)
+	close(this.stop)
+	this.wg.Wait()
+	return nil
+}
+
+// take the pending actions as a batch,nil if no action is pending,
+// must be called with mu locked
+func (this *BulkProcessor) takeBatch() *bulkBatch {
+	if len(this.actions) == 0 {
+		return nil
+	}
+	this.batchId++
+	batch := &bulkBatch{id: this.batchId, actions: this.actions, body: this.body, done: make(chan struct{})}
+	this.actions = nil
+	this.body = nil
+	this.inflight[batch.id] = batch
+	return batch
+}
+
+// the batches taken but not sent yet,must be called with mu locked
+func (this *BulkProcessor) inflightBatches() []*bulkBatch {
+	batches := make([]*bulkBatch, 0, len(this.inflight))
+	for _, batch := range this.inflight {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// wait until the batches are all sent
+func waitBatches(batches []*bulkBatch) {
+	for _, batch := range batches {
+		<-batch.done
+	}
+}
+
+func (this *BulkProcessor) work() {
+	defer this.wg.Done()
+	for {
+		select {
+		case batch := <-this.batches:
+			result, err := this.commit(batch)
+			if this.after == nil {
+				continue
+			}
+			// a new worker takes over the batches while this one runs after,
+			// so Add and Flush called in after won't wait for this worker
+			this.wg.Add(1)
+			go this.work()
+			this.after(batch.id, batch.actions, result, err)
+			return
+		case <-this.stop:
+			return
+		}
+	}
+}
+
+// send the batch and mark it done
+func (this *BulkProcessor) commit(batch *bulkBatch) (*BulkResult, error) {
+	if this.before != nil {
+		this.before(batch.id, batch.actions)
+	}
+	var result *BulkResult
+	var err error
+	if this.retrier != nil {
+		var report *BulkReport
+		report, err = this.client.BulkWithRetryContext(context.Background(), batch.actions, this.retrier, this.params...)
+		if report != nil {
+			result = report.Result()
+		}
+	} else {
+		params := bulkParams(batch.actions, this.params)
+		result, err = this.client.bulk(context.Background(), batch.body, bulkIdempotent(batch.actions), params...)
+	}
+
+	this.mu.Lock()
+	delete(this.inflight, batch.id)
+	this.mu.Unlock()
+	close(batch.done)
+	return result, err
+}
+
+func (this *BulkProcessor) flusher() {
+	defer this.wg.Done()
+	ticker := time.NewTicker(this.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			this.mu.Lock()
+			batch := this.takeBatch()
+			this.mu.Unlock()
+			if batch != nil {
+				this.batches <- batch
+			}
+		case <-this.stop:
+			return
+		}
+	}
+}
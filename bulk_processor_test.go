@@ -0,0 +1,117 @@
+package elastic
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBulkProcessor(t *testing.T) {
+	//从连接池中取得一个连接
+	v, err := MyPool.GetClient()
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	defer MyPool.PutClient(v)
+	if v == nil {
+		log.Fatalf("\n\n\n\n*******\n\n\n")
+	}
+
+	client := v.(*Client)
+	index := "test_elastic_bulk_processor"
+
+	var succeeded int64
+	processor := NewBulkProcessor(client).Workers(2).BulkActions(50).FlushInterval(time.Second).
+		After(func(id int64, actions []Action, result *BulkResult, err error) {
+			if err != nil {
+				t.Errorf("batch %d fail:%s", id, err.Error())
+				return
+			}
+			atomic.AddInt64(&succeeded, int64(len(actions)-len(result.Failed())))
+		})
+	if err := processor.Start(); err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				id := strconv.Itoa(g*100 + i)
+				err := processor.Add(Action{Index: index, DocType: "_doc", Id: id, Data: map[string]interface{}{"n": i}})
+				if err != nil {
+					t.Errorf(err.Error())
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if err := processor.Close(); err != nil {
+		log.Fatalf(err.Error())
+	}
+	if succeeded != 400 {
+		t.Errorf("400 actions must succeed,got:%d", succeeded)
+	}
+}
+
+// Flush in After must not wait for its own batch,and Action.Refresh must reach the request
+func TestBulkProcessorFlushInAfter(t *testing.T) {
+	var refreshed int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("refresh") == "true" {
+			atomic.AddInt64(&refreshed, 1)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		items := make([]string, strings.Count(string(body), "\n")/2)
+		for i := range items {
+			items[i] = `{"index":{"status":201}}`
+		}
+		w.Write([]byte(`{"took":1,"items":[` + strings.Join(items, ",") + `]}`))
+	}))
+	defer server.Close()
+	client, err := NewClient(SetUrl(server.URL))
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	defer client.Close()
+
+	var processor *BulkProcessor
+	flushed := make(chan error, 1)
+	processor = NewBulkProcessor(client).Workers(1).BulkActions(1).
+		After(func(id int64, actions []Action, result *BulkResult, err error) {
+			if id == 1 {
+				processor.Add(Action{Index: "test", DocType: "_doc", Data: map[string]interface{}{"n": 2}})
+				flushed <- processor.Flush()
+			}
+		})
+	if err := processor.Start(); err != nil {
+		log.Fatalf(err.Error())
+	}
+	refresh := 1
+	if err := processor.Add(Action{Index: "test", DocType: "_doc", Data: map[string]interface{}{"n": 1}, Refresh: &refresh}); err != nil {
+		log.Fatalf(err.Error())
+	}
+	select {
+	case err := <-flushed:
+		if err != nil {
+			t.Errorf(err.Error())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("flush in after is blocked")
+	}
+	processor.Close()
+	if atomic.LoadInt64(&refreshed) != 1 {
+		t.Errorf("the batch with Refresh must be sent with refresh=true")
+	}
+}
@@ -0,0 +1,161 @@
+package elastic
+
+import (
+	"context"
+	"errors"
+)
+
+// the final state of every action sent by BulkWithRetry,
+// Items are in the order of the actions,the others group them by how they end
+type BulkReport struct {
+	Took  int
+	Items []*BulkReportItem
+	// sent successfully,maybe after some retries
+	Succeeded []*BulkReportItem
+	// failed with a status can't be retried,like mapper_parsing_exception
+	Failed []*BulkReportItem
+	// still rejected with a retryable status after all retries
+	Exhausted []*BulkReportItem
+	// not confirmed because the whole request failed
+	Pending []*BulkReportItem
+}
+
+type BulkReportItem struct {
+	// the position of the action in the actions
+	Position int
+	Action   Action
+	// the result of the last attempt,nil if it is never answered
+	Result   *SubBulkItem
+	Attempts int
+	data     []byte
+	state    int
+}
+
+const (
+	bulkItemPending = iota
+	bulkItemSucceeded
+	bulkItemFailed
+	bulkItemExhausted
+)
+
+// group the items by their states in the order of the actions
+func (this *BulkReport) group() *BulkReport {
+	for _, item := range this.Items {
+		switch item.state {
+		case bulkItemSucceeded:
+			this.Succeeded = append(this.Succeeded, item)
+		case bulkItemFailed:
+			this.Failed = append(this.Failed, item)
+		case bulkItemExhausted:
+			this.Exhausted = append(this.Exhausted, item)
+		default:
+			this.Pending = append(this.Pending, item)
+		}
+	}
+	return this
+}
+
+// check if all actions are sent successfully
+func (this *BulkReport) Ok() bool {
+	return len(this.Succeeded) == len(this.Items)
+}
+
+// merge the last results of the items into one BulkResult in the order of the actions,
+// the pending items have no result
+func (this *BulkReport) Result() *BulkResult {
+	result := &BulkResult{Took: this.Took, Errors: !this.Ok()}
+	for _, item := range this.Items {
+		if item.Result == nil {
+			result.Items = append(result.Items, &BulkItem{})
+			continue
+		}
+		bulkItem := &BulkItem{}
+		switch item.Action.OpType {
+		case "create":
+			bulkItem.Create = item.Result
+		case "update":
+			bulkItem.Update = item.Result
+		case "delete":
+			bulkItem.Delete = item.Result
+		default:
+			bulkItem.Index = item.Result
+		}
+		result.Items = append(result.Items, bulkItem)
+	}
+	return result
+}
+
+// send the actions by Bulk and resend only the items rejected with a retryable status,like 429 and 503,
+// retrier decides the retryable status and the backoff between the requests,
+// it is the client's retrier if nil,or NewRetrier() if the client has none
+// the report is returned with the error when the whole request fails
+func (this *Client) BulkWithRetry(actions []Action, retrier *Retrier, params ...string) (*BulkReport, error) {
+	return this.BulkWithRetryContext(context.Background(), actions, retrier, params...)
+}
+
+func (this *Client) BulkWithRetryContext(ctx context.Context, actions []Action, retrier *Retrier, params ...string) (*BulkReport, error) {
+	if len(actions) == 0 {
+		return nil, errors.New("bulk must have actions")
+	}
+	if retrier == nil {
+		retrier = this.retrier
+	}
+	if retrier == nil {
+		retrier = NewRetrier()
+	}
+
+	report := &BulkReport{}
+	for i, a := range actions {
+		data, err := a.Format()
+		if err != nil {
+			return nil, err
+		}
+		report.Items = append(report.Items, &BulkReportItem{Position: i, Action: a, data: data})
+	}
+	params = bulkParams(actions, params)
+
+	pending := report.Items
+	for retry := 0; ; retry++ {
+		body := []byte{}
+		idempotent := true
+		for _, item := range pending {
+			body = append(body, item.data...)
+			body = append(body, '\n')
+			idempotent = idempotent && item.Action.Id != ""
+		}
+		result, err := this.bulk(ctx, body, idempotent, params...)
+		if err != nil {
+			return report.group(), err
+		}
+		if len(result.Items) != len(pending) {
+			return report.group(), errors.New("bulk response items don't match the actions")
+		}
+		report.Took += result.Took
+
+		var next []*BulkReportItem
+		for i, bulkItem := range result.Items {
+			item := pending[i]
+			item.Attempts++
+			item.Result = bulkItem.Result()
+			switch {
+			case item.Result == nil:
+				item.state = bulkItemFailed
+			case !item.Result.Failed():
+				item.state = bulkItemSucceeded
+			case retrier.shouldRetry(retry, item.Result.Status, nil):
+				next = append(next, item)
+			case retrier.retryable != nil && retrier.retryable(item.Result.Status, nil):
+				item.state = bulkItemExhausted
+			default:
+				item.state = bulkItemFailed
+			}
+		}
+		if len(next) == 0 {
+			return report.group(), nil
+		}
+		if err := retrier.wait(ctx, retry); err != nil {
+			return report.group(), err
+		}
+		pending = next
+	}
+}
@@ -0,0 +1,41 @@
+package elastic
+
+import (
+	"fmt"
+	"log"
+	"testing"
+)
+
+func TestBulkWithRetry(t *testing.T) {
+	//从连接池中取得一个连接
+	v, err := MyPool.GetClient()
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	defer MyPool.PutClient(v)
+	if v == nil {
+		log.Fatalf("\n\n\n\n*******\n\n\n")
+	}
+
+	client := v.(*Client)
+	index := "test_elastic_bulk_retry"
+
+	actions := []Action{
+		{Index: index, DocType: "_doc", Id: "1", Data: map[string]interface{}{"count": 1}},
+		{Index: index, DocType: "_doc", Id: "2", Data: map[string]interface{}{"count": "not a number"}},
+		{OpType: "delete", Index: index, DocType: "_doc", Id: "not_exist"},
+	}
+	report, err := client.BulkWithRetry(actions, nil, Param("refresh", "true"))
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	if len(report.Succeeded) != 2 {
+		t.Errorf("2 actions must succeed,got:%d", len(report.Succeeded))
+	}
+	if len(report.Failed) != 1 || report.Failed[0].Action.Id != "2" {
+		t.Errorf("the action with id 2 must fail")
+	}
+	for _, item := range report.Failed {
+		fmt.Printf("%d %s %s\n", item.Position, item.Result.Error.Type, item.Result.Error.Reason)
+	}
+}
@@ -0,0 +1,99 @@
+package elastic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// the result of delete by query,update by query and reindex
+// if wait_for_completion=false is set,only Task is returned
+type ByQueryResult struct {
+	Took                 int64                    `json:"took,omitempty"`
+	TimedOut             bool                     `json:"timed_out,omitempty"`
+	Total                int64                    `json:"total"`
+	Updated              int64                    `json:"updated"`
+	Created              int64                    `json:"created"`
+	Deleted              int64                    `json:"deleted"`
+	Batches              int64                    `json:"batches"`
+	VersionConflicts     int64                    `json:"version_conflicts"`
+	Noops                int64                    `json:"noops"`
+	Retries              *Retries                 `json:"retries,omitempty"`
+	ThrottledMillis      int64                    `json:"throttled_millis"`
+	RequestsPerSecond    float64                  `json:"requests_per_second"`
+	ThrottledUntilMillis int64                    `json:"throttled_until_millis"`
+	Failures             []map[string]interface{} `json:"failures,omitempty"`
+	Task                 string                   `json:"task,omitempty"`
+	Error                *Error                   `json:"error,omitempty"`
+	Status               int                      `json:"status,omitempty"`
+}
+
+// delete the documents match query
+// params can be conflicts=proceed,slices,requests_per_second,scroll_size,wait_for_completion,refresh,routing
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.3/docs-delete-by-query.html
+func (this *Client) DeleteByQuery(index string, docType string, query Query, params ...string) (*ByQueryResult, error) {
+	return this.DeleteByQueryContext(context.Background(), index, docType, query, params...)
+}
+
+func (this *Client) DeleteByQueryContext(ctx context.Context, index string, docType string, query Query, params ...string) (*ByQueryResult, error) {
+	if index == "" {
+		return nil, errors.New("delete by query must have index")
+	}
+	if query == nil {
+		return nil, errors.New("delete by query must have query")
+	}
+	body, err := buildQueryBody(query)
+	if err != nil {
+		return nil, err
+	}
+	return this.byQuery(ctx, buildPath(params, index, docType, "_delete_by_query"), body)
+}
+
+// update the documents match query by script,query can be nil to update all documents
+// and script can be nil to only reindex the documents in place
+// params can be conflicts=proceed,slices,requests_per_second,scroll_size,wait_for_completion,refresh,routing,pipeline
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.3/docs-update-by-query.html
+func (this *Client) UpdateByQuery(index string, docType string, query Query, script *Script, params ...string) (*ByQueryResult, error) {
+	return this.UpdateByQueryContext(context.Background(), index, docType, query, script, params...)
+}
+
+func (this *Client) UpdateByQueryContext(ctx context.Context, index string, docType string, query Query, script *Script, params ...string) (*ByQueryResult, error) {
+	if index == "" {
+		return nil, errors.New("update by query must have index")
+	}
+	body, err := buildQueryMap(query)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		body = make(map[string]interface{})
+	}
+	if script != nil {
+		s, err := script.BuildBody()
+		if err != nil {
+			return nil, err
+		}
+		body["script"] = s
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return this.byQuery(ctx, buildPath(params, index, docType, "_update_by_query"), data)
+}
+
+func (this *Client) byQuery(ctx context.Context, path string, body []byte) (*ByQueryResult, error) {
+	response, err := this.performRequest(ctx, "POST", path, body, "application/json;charset=UTF-8")
+	if err != nil {
+		return nil, err
+	}
+	result := new(ByQueryResult)
+	err = this.decodeResponse(ctx, response, result)
+	if err != nil {
+		if AsElasticError(err) != nil {
+			return result, err
+		}
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,277 @@
+package elastic
+
+import "context"
+
+// the numbers of cat api are strings in json,they are decoded by the ",string" option,
+// the sizes are in bytes and the times are in milliseconds
+type CatIndex struct {
+	Health       string `json:"health,omitempty"`
+	Status       string `json:"status,omitempty"`
+	Index        string `json:"index,omitempty"`
+	Uuid         string `json:"uuid,omitempty"`
+	Pri          int    `json:"pri,string"`
+	Rep          int    `json:"rep,string"`
+	DocsCount    int64  `json:"docs.count,string"`
+	DocsDeleted  int64  `json:"docs.deleted,string"`
+	StoreSize    int64  `json:"store.size,string"`
+	PriStoreSize int64  `json:"pri.store.size,string"`
+}
+
+type CatShard struct {
+	Index            string `json:"index,omitempty"`
+	Shard            int    `json:"shard,string"`
+	Prirep           string `json:"prirep,omitempty"`
+	State            string `json:"state,omitempty"`
+	Docs             int64  `json:"docs,string"`
+	Store            int64  `json:"store,string"`
+	Ip               string `json:"ip,omitempty"`
+	Node             string `json:"node,omitempty"`
+	UnassignedReason string `json:"unassigned.reason,omitempty"`
+}
+
+type CatNode struct {
+	Ip          string  `json:"ip,omitempty"`
+	Name        string  `json:"name,omitempty"`
+	NodeRole    string  `json:"node.role,omitempty"`
+	Master      string  `json:"master,omitempty"`
+	HeapPercent int     `json:"heap.percent,string"`
+	HeapCurrent int64   `json:"heap.current,string"`
+	HeapMax     int64   `json:"heap.max,string"`
+	RamPercent  int     `json:"ram.percent,string"`
+	RamCurrent  int64   `json:"ram.current,string"`
+	RamMax      int64   `json:"ram.max,string"`
+	Cpu         int     `json:"cpu,string"`
+	Load1m      float64 `json:"load_1m,string"`
+	Load5m      float64 `json:"load_5m,string"`
+	Load15m     float64 `json:"load_15m,string"`
+	DiskAvail   int64   `json:"disk.avail,string"`
+	Uptime      int64   `json:"uptime,string"`
+}
+
+type CatAllocation struct {
+	Shards      int    `json:"shards,string"`
+	DiskIndices int64  `json:"disk.indices,string"`
+	DiskUsed    int64  `json:"disk.used,string"`
+	DiskAvail   int64  `json:"disk.avail,string"`
+	DiskTotal   int64  `json:"disk.total,string"`
+	DiskPercent int    `json:"disk.percent,string"`
+	Host        string `json:"host,omitempty"`
+	Ip          string `json:"ip,omitempty"`
+	Node        string `json:"node,omitempty"`
+}
+
+// index can be empty for all indices,or many indices joined by comma or wildcards
+// params can be health,pri,s,local,master_timeout
+func (this *Client) CatIndices(index string, params ...string) ([]*CatIndex, error) {
+	return this.CatIndicesContext(context.Background(), index, params...)
+}
+
+func (this *Client) CatIndicesContext(ctx context.Context, index string, params ...string) ([]*CatIndex, error) {
+	result := make([]*CatIndex, 0)
+	columns := "health,status,index,uuid,pri,rep,docs.count,docs.deleted,store.size,pri.store.size"
+	err := this.cat(ctx, &result, columns, params, "indices", index)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (this *Client) CatShards(index string, params ...string) ([]*CatShard, error) {
+	return this.CatShardsContext(context.Background(), index, params...)
+}
+
+func (this *Client) CatShardsContext(ctx context.Context, index string, params ...string) ([]*CatShard, error) {
+	result := make([]*CatShard, 0)
+	columns := "index,shard,prirep,state,docs,store,ip,node,unassigned.reason"
+	err := this.cat(ctx, &result, columns, params, "shards", index)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (this *Client) CatNodes(params ...string) ([]*CatNode, error) {
+	return this.CatNodesContext(context.Background(), params...)
+}
+
+func (this *Client) CatNodesContext(ctx context.Context, params ...string) ([]*CatNode, error) {
+	result := make([]*CatNode, 0)
+	columns := "ip,name,node.role,master,heap.percent,heap.current,heap.max,ram.percent,ram.current,ram.max," +
+		"cpu,load_1m,load_5m,load_15m,disk.avail,uptime"
+	err := this.cat(ctx, &result, columns, params, "nodes")
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// nodeId can be empty for all nodes
+func (this *Client) CatAllocation(nodeId string, params ...string) ([]*CatAllocation, error) {
+	return this.CatAllocationContext(context.Background(), nodeId, params...)
+}
+
+func (this *Client) CatAllocationContext(ctx context.Context, nodeId string, params ...string) ([]*CatAllocation, error) {
+	result := make([]*CatAllocation, 0)
+	columns := "shards,disk.indices,disk.used,disk.avail,disk.total,disk.percent,host,ip,node"
+	err := this.cat(ctx, &result, columns, params, "allocation", nodeId)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// request the cat api in json with bytes and times as numbers,
+// the columns are used unless the params have h
+func (this *Client) cat(ctx context.Context, result interface{}, columns string, params []string, parts ...string) error {
+	catParams := []string{Param("format", "json"), Param("bytes", "b"), Param("time", "ms")}
+	if !hasParam(params, "h") {
+		catParams = append(catParams, Param("h", columns))
+	}
+	catParams = append(catParams, params...)
+	response, err := this.performRequest(ctx, "GET", buildPath(catParams, append([]string{"_cat"}, parts...)...), nil, "")
+	if err != nil {
+		return err
+	}
+	return this.decodeResponse(ctx, response, result)
+}
+
+type NodesStatsResult struct {
+	ClusterName string                `json:"cluster_name,omitempty"`
+	Nodes       map[string]*NodeStats `json:"nodes,omitempty"`
+	Error       *Error                `json:"error,omitempty"`
+	Status      int                   `json:"status,omitempty"`
+}
+
+// the stats of one node,only the requested metrics are set
+type NodeStats struct {
+	Timestamp        int64                       `json:"timestamp,omitempty"`
+	Name             string                      `json:"name,omitempty"`
+	TransportAddress string                      `json:"transport_address,omitempty"`
+	Host             string                      `json:"host,omitempty"`
+	Ip               string                      `json:"ip,omitempty"`
+	Roles            []string                    `json:"roles,omitempty"`
+	Indices          *NodeIndicesStats           `json:"indices,omitempty"`
+	Os               *NodeOsStats                `json:"os,omitempty"`
+	Process          *NodeProcessStats           `json:"process,omitempty"`
+	Jvm              *NodeJvmStats               `json:"jvm,omitempty"`
+	ThreadPool       map[string]*ThreadPoolStats `json:"thread_pool,omitempty"`
+	Fs               *NodeFsStats                `json:"fs,omitempty"`
+	Breakers         map[string]*BreakerStats    `json:"breakers,omitempty"`
+}
+
+type NodeIndicesStats struct {
+	Docs struct {
+		Count   int64 `json:"count"`
+		Deleted int64 `json:"deleted"`
+	} `json:"docs"`
+	Store struct {
+		SizeInBytes int64 `json:"size_in_bytes"`
+	} `json:"store"`
+	Indexing struct {
+		IndexTotal        int64 `json:"index_total"`
+		IndexTimeInMillis int64 `json:"index_time_in_millis"`
+		IndexFailed       int64 `json:"index_failed"`
+	} `json:"indexing"`
+	Search struct {
+		QueryTotal        int64 `json:"query_total"`
+		QueryTimeInMillis int64 `json:"query_time_in_millis"`
+		FetchTotal        int64 `json:"fetch_total"`
+		FetchTimeInMillis int64 `json:"fetch_time_in_millis"`
+	} `json:"search"`
+	Segments struct {
+		Count         int64 `json:"count"`
+		MemoryInBytes int64 `json:"memory_in_bytes"`
+	} `json:"segments"`
+}
+
+type NodeOsStats struct {
+	Cpu struct {
+		Percent     int                `json:"percent"`
+		LoadAverage map[string]float64 `json:"load_average,omitempty"`
+	} `json:"cpu"`
+	Mem struct {
+		TotalInBytes int64 `json:"total_in_bytes"`
+		FreeInBytes  int64 `json:"free_in_bytes"`
+		UsedInBytes  int64 `json:"used_in_bytes"`
+		FreePercent  int   `json:"free_percent"`
+		UsedPercent  int   `json:"used_percent"`
+	} `json:"mem"`
+}
+
+type NodeProcessStats struct {
+	OpenFileDescriptors int64 `json:"open_file_descriptors"`
+	MaxFileDescriptors  int64 `json:"max_file_descriptors"`
+	Cpu                 struct {
+		Percent       int   `json:"percent"`
+		TotalInMillis int64 `json:"total_in_millis"`
+	} `json:"cpu"`
+}
+
+type NodeJvmStats struct {
+	UptimeInMillis int64 `json:"uptime_in_millis"`
+	Mem            struct {
+		HeapUsedInBytes         int64 `json:"heap_used_in_bytes"`
+		HeapUsedPercent         int   `json:"heap_used_percent"`
+		HeapCommittedInBytes    int64 `json:"heap_committed_in_bytes"`
+		HeapMaxInBytes          int64 `json:"heap_max_in_bytes"`
+		NonHeapUsedInBytes      int64 `json:"non_heap_used_in_bytes"`
+		NonHeapCommittedInBytes int64 `json:"non_heap_committed_in_bytes"`
+	} `json:"mem"`
+	Threads struct {
+		Count     int `json:"count"`
+		PeakCount int `json:"peak_count"`
+	} `json:"threads"`
+	Gc struct {
+		Collectors map[string]*GcCollectorStats `json:"collectors,omitempty"`
+	} `json:"gc"`
+}
+
+type GcCollectorStats struct {
+	CollectionCount        int64 `json:"collection_count"`
+	CollectionTimeInMillis int64 `json:"collection_time_in_millis"`
+}
+
+type ThreadPoolStats struct {
+	Threads   int   `json:"threads"`
+	Queue     int   `json:"queue"`
+	Active    int   `json:"active"`
+	Rejected  int64 `json:"rejected"`
+	Largest   int   `json:"largest"`
+	Completed int64 `json:"completed"`
+}
+
+type NodeFsStats struct {
+	Timestamp int64 `json:"timestamp"`
+	Total     struct {
+		TotalInBytes     int64 `json:"total_in_bytes"`
+		FreeInBytes      int64 `json:"free_in_bytes"`
+		AvailableInBytes int64 `json:"available_in_bytes"`
+	} `json:"total"`
+}
+
+type BreakerStats struct {
+	LimitSizeInBytes     int64   `json:"limit_size_in_bytes"`
+	EstimatedSizeInBytes int64   `json:"estimated_size_in_bytes"`
+	Overhead             float64 `json:"overhead"`
+	Tripped              int64   `json:"tripped"`
+}
+
+// nodeId can be empty for all nodes,or like "_local","node1,node2"
+// metrics can be indices,os,process,jvm,thread_pool,fs,transport,http,breaker,script,discovery,ingest,
+// empty for all metrics
+func (this *Client) NodesStats(nodeId string, metrics ...string) (*NodesStatsResult, error) {
+	return this.NodesStatsContext(context.Background(), nodeId, metrics...)
+}
+
+func (this *Client) NodesStatsContext(ctx context.Context, nodeId string, metrics ...string) (*NodesStatsResult, error) {
+	response, err := this.performRequest(ctx, "GET", buildPath(nil, "_nodes", nodeId, "stats", joinNames(metrics)), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	result := new(NodesStatsResult)
+	err = this.decodeResponse(ctx, response, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
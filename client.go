@@ -1,34 +1,65 @@
 package elastic
 
 import (
-	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 )
 
 type Client struct {
-	client          *http.Client
-	url             string
-	basicAuthUser   string
-	basicAuthPasswd string
-	timeOut         time.Duration
+	client              *http.Client
+	urls                []string
+	nodes               *nodeList
+	selector            string
+	healthcheckInterval time.Duration
+	healthcheckSet      bool
+	sniff               bool
+	snifferInterval     time.Duration
+	snifferFilter       func(node *NodeInfo) bool
+	stop                chan struct{}
+	closeOnce           sync.Once
+	basicAuthUser       string
+	basicAuthPasswd     string
+	timeOut             time.Duration
+	retrier             *Retrier
 }
 
 type ClientOptionFunc func(*Client)
 
 // create a Client
+// it may start goroutines for the health check and sniffing,
+// call Close when the client is no longer used,or they are leaked
 func NewClient(options ...ClientOptionFunc) (*Client, error) {
-	this := &Client{}
+	this := &Client{
+		selector:            ROUND_ROBIN,
+		healthcheckInterval: DEFAULT_HEALTHCHECK_INTERVAL,
+		snifferInterval:     DEFAULT_SNIFFER_INTERVAL,
+	}
 	for _, op := range options {
 		op(this)
 	}
 
 	this.client = &http.Client{Timeout: this.timeOut}
+	this.nodes = newNodeList(this.urls, this.selector)
+	if this.sniff {
+		ctx, cancel := context.WithTimeout(context.Background(), DEFAULT_SNIFFER_TIMEOUT)
+		err := this.SniffContext(ctx)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+	}
+	this.stop = make(chan struct{})
+	this.startHealthcheck()
+	this.startSniffer()
 
 	return this, nil
 }
@@ -42,7 +73,31 @@ func SetBasicAuth(user string, passwd string) ClientOptionFunc {
 
 func SetUrl(url string) ClientOptionFunc {
 	return func(this *Client) {
-		this.url = url
+		this.urls = []string{url}
+	}
+}
+
+// send requests to many nodes,a node is marked dead when it can't be connected
+// and is used again after the health check finds it alive
+func SetUrls(urls ...string) ClientOptionFunc {
+	return func(this *Client) {
+		this.urls = urls
+	}
+}
+
+// how to pick a node for every request,ROUND_ROBIN or RANDOM
+func SetNodeSelector(selector string) ClientOptionFunc {
+	return func(this *Client) {
+		this.selector = selector
+	}
+}
+
+// how often to check if the dead nodes are alive again,0 means never check
+// by default the check only runs with many nodes or sniffing
+func SetHealthcheckInterval(interval int) ClientOptionFunc {
+	return func(this *Client) {
+		this.healthcheckInterval = time.Duration(interval) * time.Second
+		this.healthcheckSet = true
 	}
 }
 
@@ -52,172 +107,464 @@ func SetTimeOut(timeOut int) ClientOptionFunc {
 	}
 }
 
+// retry the failed requests by retrier,nil means never retry
+func SetRetrier(retrier *Retrier) ClientOptionFunc {
+	return func(this *Client) {
+		this.retrier = retrier
+	}
+}
+
 func (this *Client) buildUrl(index string, docType string, params ...string) string {
 	if len(params) > 0 {
-		return fmt.Sprintf("%s/%s/%s/_search?%s", this.url, index, docType, strings.Join(params, "&"))
+		return fmt.Sprintf("/%s/%s/_search?%s", index, docType, strings.Join(params, "&"))
 	} else {
-		return fmt.Sprintf("%s/%s/%s/_search", this.url, index, docType)
+		return fmt.Sprintf("/%s/%s/_search", index, docType)
 	}
 
 }
-func (this *Client) buildRequest(method string, url string, query Query) (*http.Request, error) {
+
+func (this *Client) buildBody(query Query) ([]byte, error) {
 	b, err := query.BuildBody()
 	if err != nil {
 		return nil, err
 	}
-	body, err := json.Marshal(b)
-	if err != nil {
-		return nil, err
+	return json.Marshal(b)
+}
+
+// send a request to a node picked from the nodes,path is the part of url after the node's address
+// the request will be canceled when ctx is done,
+// if it is canceled or out of deadline,ctx.Err() will be returned instead of the transport error
+// failed requests are sent again with the same body if a retrier is set,
+// POST is taken as not idempotent,use performIdempotentRequest for the POSTs can be sent twice
+func (this *Client) performRequest(ctx context.Context, method string, path string, body []byte, contentType string) (*http.Response, error) {
+	return this.doRequest(ctx, method, path, body, contentType, method != "POST")
+}
+
+// like performRequest,but the request can be sent again on any connection error,
+// for the POSTs only reading or with the same result when applied twice,like _search and _refresh
+func (this *Client) performIdempotentRequest(ctx context.Context, method string, path string, body []byte, contentType string) (*http.Response, error) {
+	return this.doRequest(ctx, method, path, body, contentType, true)
+}
+
+// a request not idempotent is not retried on the connection errors after it may be sent
+func (this *Client) doRequest(ctx context.Context, method string, path string, body []byte, contentType string, idempotent bool) (*http.Response, error) {
+	for retry := 0; ; retry++ {
+		n, err := this.nodes.pick()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := this.sendRequest(ctx, method, n.url+path, body, contentType)
+		// only the node can't be connected is dead,not the slow or bad requests
+		if err != nil && ctx.Err() == nil && isDialError(err) {
+			this.nodes.markDead(n)
+		} else if err == nil {
+			this.nodes.markAlive(n)
+		}
+		if this.retrier == nil || ctx.Err() != nil {
+			return resp, err
+		}
+		if err != nil && !idempotent && !isDialError(err) {
+			return resp, err
+		}
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		if !this.retrier.shouldRetry(retry, status, err) {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := this.retrier.wait(ctx, retry); err != nil {
+			return nil, err
+		}
 	}
+}
 
-	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+func (this *Client) sendRequest(ctx context.Context, method string, url string, body []byte, contentType string) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
 	if err != nil {
 		return nil, err
 	}
 	req.SetBasicAuth(this.basicAuthUser, this.basicAuthPasswd)
-	req.Header.Set("Content-Type", "application/json;charset=UTF-8")
-	return req, nil
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := this.client.Do(req)
+	if err != nil {
+		return nil, contextError(ctx, err)
+	}
+	return resp, nil
+}
+
+// if ctx is done return ctx.Err(),so the caller can tell context.Canceled
+// and context.DeadlineExceeded from other failures
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
 }
 
 func (this *Client) Search(index string, docType string, query Query, params ...string) (*SearchResult, error) {
+	return this.SearchContext(context.Background(), index, docType, query, params...)
+}
+
+func (this *Client) SearchContext(ctx context.Context, index string, docType string, query Query, params ...string) (*SearchResult, error) {
 	url := this.buildUrl(index, docType, params...)
-	req, err := this.buildRequest("POST", url, query)
+	body, err := this.buildBody(query)
 	if err != nil {
 		return nil, err
 	}
-	resp, err := this.client.Do(req)
+	resp, err := this.performIdempotentRequest(ctx, "POST", url, body, "application/json;charset=UTF-8")
 	if err != nil {
 		return nil, err
 	}
 	result := new(SearchResult)
-	return this.buildResult(resp, result)
+	return this.buildResult(ctx, resp, result)
 }
 
-func (this *Client) Scroll(params map[string]string) (*SearchResult, error) {
-	url := this.url + "/_search/scroll"
-	body, err := json.Marshal(params)
+// one search of multi search
+type MultiSearchItem struct {
+	index   string
+	docType string
+	query   *QueryBody
+	header  map[string]interface{}
+}
+
+func NewMultiSearchItem(index string, docType string, query *QueryBody) *MultiSearchItem {
+	return &MultiSearchItem{index: index, docType: docType, query: query}
+}
+
+func (this *MultiSearchItem) Routing(routing string) *MultiSearchItem {
+	return this.Header("routing", routing)
+}
+
+func (this *MultiSearchItem) Preference(preference string) *MultiSearchItem {
+	return this.Header("preference", preference)
+}
+
+func (this *MultiSearchItem) SearchType(searchType string) *MultiSearchItem {
+	return this.Header("search_type", searchType)
+}
+
+func (this *MultiSearchItem) RequestCache(requestCache bool) *MultiSearchItem {
+	return this.Header("request_cache", requestCache)
+}
+
+// other search params of the header line add by this func
+func (this *MultiSearchItem) Header(key string, value interface{}) *MultiSearchItem {
+	if this.header == nil {
+		this.header = make(map[string]interface{})
+	}
+	this.header[key] = value
+	return this
+}
+
+// return the header line and the body line
+func (this *MultiSearchItem) Format() ([]byte, error) {
+	if this.query == nil {
+		return nil, errors.New("multi search item must have query")
+	}
+	header := make(map[string]interface{})
+	for k, v := range this.header {
+		header[k] = v
+	}
+	if this.index != "" {
+		header["index"] = this.index
+	}
+	if this.docType != "" {
+		header["type"] = this.docType
+	}
+	headerByte, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	bodyByte, err := this.buildBody()
 	if err != nil {
 		return nil, err
 	}
+	headerByte = append(headerByte, []byte("\n")...)
+	return append(headerByte, bodyByte...), nil
+}
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+func (this *MultiSearchItem) buildBody() ([]byte, error) {
+	b, err := this.query.BuildBody()
 	if err != nil {
 		return nil, err
 	}
-	req.SetBasicAuth(this.basicAuthUser, this.basicAuthPasswd)
-	req.Header.Set("Content-Type", "application/json;charset=UTF-8")
-	resp, err := this.client.Do(req)
+	return json.Marshal(b)
+}
+
+type MultiSearchResult struct {
+	Took      int             `json:"took,omitempty"`
+	Responses []*SearchResult `json:"responses,omitempty"`
+	Error     *Error          `json:"error,omitempty"`
+	Status    int             `json:"status,omitempty"`
+}
+
+// run many searches in one request,the results are in the order of items
+// a failed search has its Error set and doesn't fail the others,use SearchResult.Err to check it
+// params can be max_concurrent_searches,search_type,typed_keys
+func (this *Client) MultiSearch(items []*MultiSearchItem, params ...string) ([]*SearchResult, error) {
+	return this.MultiSearchContext(context.Background(), items, params...)
+}
+
+func (this *Client) MultiSearchContext(ctx context.Context, items []*MultiSearchItem, params ...string) ([]*SearchResult, error) {
+	if len(items) == 0 {
+		return nil, errors.New("multi search must have items")
+	}
+	body := []byte{}
+	for _, item := range items {
+		data, err := item.Format()
+		if err != nil {
+			return nil, err
+		}
+		body = append(body, data...)
+		body = append(body, []byte("\n")...)
+	}
+
+	response, err := this.performIdempotentRequest(ctx, "POST", buildPath(params, "_msearch"), body, "application/x-ndjson")
 	if err != nil {
 		return nil, err
 	}
-	result := new(SearchResult)
-	return this.buildResult(resp, result)
+	result := new(MultiSearchResult)
+	err = this.decodeResponse(ctx, response, result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Responses) != len(items) {
+		return nil, fmt.Errorf("multi search fail,%d responses for %d searches", len(result.Responses), len(items))
+	}
+	return result.Responses, nil
 }
 
-func (this *Client) ClearScroll(scrollIds ...string) (*ClearScrollResp, error) {
-	url := this.url + "/_search/scroll"
-	body, err := json.Marshal(map[string][]string{"scroll_id": scrollIds})
+type CountResult struct {
+	Count  int64   `json:"count"`
+	Shards *Shards `json:"_shards,omitempty"`
+	Error  *Error  `json:"error,omitempty"`
+	Status int     `json:"status,omitempty"`
+}
+
+// count the documents match query,query can be nil to count all documents
+// if query is a *QueryBody,only its query part is used
+func (this *Client) Count(index string, docType string, query Query, params ...string) (*CountResult, error) {
+	return this.CountContext(context.Background(), index, docType, query, params...)
+}
+
+func (this *Client) CountContext(ctx context.Context, index string, docType string, query Query, params ...string) (*CountResult, error) {
+	body, err := buildQueryBody(query)
+	if err != nil {
+		return nil, err
+	}
+	response, err := this.performIdempotentRequest(ctx, "POST", buildPath(params, index, docType, "_count"), body, "application/json;charset=UTF-8")
 	if err != nil {
 		return nil, err
 	}
+	result := new(CountResult)
+	err = this.decodeResponse(ctx, response, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// build {"query":{...}} for the apis only accept query,like _count and _delete_by_query
+func buildQueryBody(query Query) ([]byte, error) {
+	body, err := buildQueryMap(query)
+	if err != nil || body == nil {
+		return nil, err
+	}
+	return json.Marshal(body)
+}
 
-	req, err := http.NewRequest("DELETE", url, bytes.NewReader(body))
+// if query is a *QueryBody,only its query part is used
+func buildQueryMap(query Query) (map[string]interface{}, error) {
+	if queryBody, ok := query.(*QueryBody); ok {
+		if queryBody == nil {
+			return nil, nil
+		}
+		query = queryBody.query
+	}
+	if query == nil {
+		return nil, nil
+	}
+	q, err := query.BuildBody()
 	if err != nil {
 		return nil, err
 	}
-	req.SetBasicAuth(this.basicAuthUser, this.basicAuthPasswd)
-	req.Header.Set("Content-Type", "application/json;charset=UTF-8")
+	return map[string]interface{}{"query": q}, nil
+}
 
-	response, err := this.client.Do(req)
+func (this *Client) Scroll(params map[string]string) (*SearchResult, error) {
+	return this.ScrollContext(context.Background(), params)
+}
+
+func (this *Client) ScrollContext(ctx context.Context, params map[string]string) (*SearchResult, error) {
+	url := "/_search/scroll"
+	body, err := json.Marshal(params)
 	if err != nil {
 		return nil, err
 	}
 
-	result := new(ClearScrollResp)
+	resp, err := this.performIdempotentRequest(ctx, "POST", url, body, "application/json;charset=UTF-8")
+	if err != nil {
+		return nil, err
+	}
+	result := new(SearchResult)
+	return this.buildResult(ctx, resp, result)
+}
 
-	defer response.Body.Close()
+func (this *Client) ClearScroll(scrollIds ...string) (*ClearScrollResp, error) {
+	return this.ClearScrollContext(context.Background(), scrollIds...)
+}
 
-	err = json.NewDecoder(response.Body).Decode(result)
+func (this *Client) ClearScrollContext(ctx context.Context, scrollIds ...string) (*ClearScrollResp, error) {
+	url := "/_search/scroll"
+	body, err := json.Marshal(map[string][]string{"scroll_id": scrollIds})
+	if err != nil {
+		return nil, err
+	}
 
+	response, err := this.performRequest(ctx, "DELETE", url, body, "application/json;charset=UTF-8")
 	if err != nil {
 		return nil, err
 	}
-	if response.StatusCode != 200 {
-		reason := fmt.Sprintf("clear scroll fail,reason:%s", result.Error.Reason)
-		return result, errors.New(reason)
+
+	result := new(ClearScrollResp)
+
+	err = this.decodeResponse(ctx, response, result)
+	if err != nil {
+		if AsElasticError(err) != nil {
+			return result, err
+		}
+		return nil, err
 	}
 	return result, nil
 
 }
 
-func (this *Client) buildResult(response *http.Response, result *SearchResult) (*SearchResult, error) {
-	defer response.Body.Close()
+func (this *Client) buildResult(ctx context.Context, response *http.Response, result *SearchResult) (*SearchResult, error) {
+	err := this.decodeResponse(ctx, response, result)
+	if err != nil {
+		if AsElasticError(err) != nil {
+			return result, err
+		}
+		return nil, err
+	}
+	return result, nil
+}
 
-	err := json.NewDecoder(response.Body).Decode(result)
+// decode the response body into result and close it
+// if elastic response a failure status,an *ElasticError will be returned
+// and result is still filled as far as the body can be decoded
+func (this *Client) decodeResponse(ctx context.Context, response *http.Response, result interface{}) error {
+	defer response.Body.Close()
 
+	body, err := ioutil.ReadAll(response.Body)
 	if err != nil {
-		return nil, err
+		return contextError(ctx, err)
 	}
-	if response.StatusCode != 200 {
-		reason := fmt.Sprintf("search fail,reason:%s", result.Error.Reason)
-		return result, errors.New(reason)
+
+	failed := response.StatusCode < 200 || response.StatusCode >= 300
+	if result != nil && len(body) > 0 {
+		if err := json.Unmarshal(body, result); err != nil && !failed {
+			return err
+		}
 	}
-	return result, nil
+	if failed {
+		return createElasticError(response.StatusCode, body)
+	}
+	return nil
 }
 
 // check if cant connect to elastic
 // if can't return false
 func (this *Client) Ping() (bool, error) {
-	req, err := http.NewRequest("GET", this.url, nil)
+	return this.PingContext(context.Background())
+}
+
+func (this *Client) PingContext(ctx context.Context) (bool, error) {
+	resp, err := this.performRequest(ctx, "GET", "/", nil, "")
 	if err != nil {
 		return false, err
 	}
-	req.SetBasicAuth(this.basicAuthUser, this.basicAuthPasswd)
-
-	resp, err := this.client.Do(req)
+	err = this.decodeResponse(ctx, resp, nil)
 	if err != nil {
 		return false, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		errorStr := fmt.Sprintf("can't connect to elastic,bad code:%d", resp.StatusCode)
-		return false, errors.New(errorStr)
-	}
 	return true, nil
 }
 
-// todo need dubug and fix
-func (this *Client) Bulk(actions []Action) (*BulkResult, error) {
+// send many actions in one request,params can be refresh,wait_for_active_shards,timeout,pipeline
+// it is only retried on the connection errors when all actions have ids
+// refresh is a param of the whole request,it is set to true if any action sets Refresh to non zero
+// the request may succeed while some items fail,check BulkResult.Errors and the status of every item
+func (this *Client) Bulk(actions []Action, params ...string) (*BulkResult, error) {
+	return this.BulkContext(context.Background(), actions, params...)
+}
+
+func (this *Client) BulkContext(ctx context.Context, actions []Action, params ...string) (*BulkResult, error) {
+	if len(actions) == 0 {
+		return nil, errors.New("bulk must have actions")
+	}
 	body := []byte{}
 	for _, a := range actions {
 		data, err := a.Format()
 		if err != nil {
 			return nil, err
 		}
-		body = append(body, []byte("\n")...)
 		body = append(body, data...)
+		body = append(body, '\n')
 	}
-	body = append(body, []byte("\n")...)
+	return this.bulk(ctx, body, bulkIdempotent(actions), bulkParams(actions, params)...)
+}
 
-	req, err := http.NewRequest("POST", this.url+"/_bulk", bufio.NewReader(bytes.NewReader(body)))
-	if err != nil {
-		return nil, err
+// add refresh=true to params if any action sets Refresh to non zero,params is not modified
+func bulkParams(actions []Action, params []string) []string {
+	if hasParam(params, "refresh") {
+		return params
 	}
-	req.SetBasicAuth(this.basicAuthUser, this.basicAuthPasswd)
-	req.Header.Set("Content-Type", "application/x-ndjson")
+	for _, a := range actions {
+		if a.Refresh != nil && *a.Refresh != 0 {
+			return append(append([]string{}, params...), Param("refresh", "true"))
+		}
+	}
+	return params
+}
+
+// the actions with ids can be sent twice without duplicated documents
+func bulkIdempotent(actions []Action) bool {
+	for _, a := range actions {
+		if a.Id == "" {
+			return false
+		}
+	}
+	return true
+}
 
-	response, err := this.client.Do(req)
+// send the formatted ndjson body,every line of body must end with \n
+// idempotent tells if the body can be sent again on connection errors
+func (this *Client) bulk(ctx context.Context, body []byte, idempotent bool, params ...string) (*BulkResult, error) {
+	response, err := this.doRequest(ctx, "POST", buildPath(params, "_bulk"), body, "application/x-ndjson", idempotent)
 	if err != nil {
 		return nil, err
 	}
 
-	defer response.Body.Close()
-
 	bulkResult := new(BulkResult)
 
-	err = json.NewDecoder(response.Body).Decode(bulkResult)
-
+	err = this.decodeResponse(ctx, response, bulkResult)
 	if err != nil {
+		if AsElasticError(err) != nil {
+			return bulkResult, err
+		}
 		return nil, err
 	}
 
@@ -225,103 +572,57 @@ func (this *Client) Bulk(actions []Action) (*BulkResult, error) {
 
 }
 
+// stop the health check and sniffer goroutines and close the idle connections,
+// it must be called when the client is no longer used
 func (this *Client) Close() error {
+	this.closeOnce.Do(func() {
+		close(this.stop)
+	})
 	this.client.CloseIdleConnections()
 	return nil
 }
 
 func (this *Client) ExistIndex(index string) (bool, error) {
-	req, err := http.NewRequest("GET", this.url+"/"+index, nil)
-	req.SetBasicAuth(this.basicAuthUser, this.basicAuthPasswd)
-	req.Header.Set("Content-Type", "application/x-ndjson")
+	return this.ExistIndexContext(context.Background(), index)
+}
 
-	response, err := this.client.Do(req)
+func (this *Client) ExistIndexContext(ctx context.Context, index string) (bool, error) {
+	response, err := this.performRequest(ctx, "GET", "/"+index, nil, "application/x-ndjson")
 	if err != nil {
 		return false, err
 	}
-	defer response.Body.Close()
-
-	result := new(SearchResult)
-
-	err = json.NewDecoder(response.Body).Decode(result)
+	err = this.decodeResponse(ctx, response, nil)
 	if err != nil {
+		if IsNotFound(err) {
+			return false, nil
+		}
 		return false, err
 	}
-	if result.Error == nil {
-		return true, nil
-	}
-	return false, nil
+	return true, nil
 }
 
 func (this *Client) CreateIndex(index string, body []byte) error {
-	req, err := http.NewRequest("PUT", this.url+"/"+index, bytes.NewReader(body))
-	if err != nil {
-		return err
-	}
-
-	req.SetBasicAuth(this.basicAuthUser, this.basicAuthPasswd)
-	req.Header.Set("Content-Type", "application/json")
+	return this.CreateIndexContext(context.Background(), index, body)
+}
 
-	response, err := this.client.Do(req)
+func (this *Client) CreateIndexContext(ctx context.Context, index string, body []byte) error {
+	response, err := this.performRequest(ctx, "PUT", "/"+index, body, "application/json")
 	if err != nil {
 		return err
 	}
-	defer response.Body.Close()
-
 	result := new(CreateIndexResult)
 
-	err = json.NewDecoder(response.Body).Decode(result)
-
-	if err != nil {
-		return err
-	}
-	if result.Error != nil {
-		return errors.New(result.Error.Reason)
-	}
-	return nil
+	return this.decodeResponse(ctx, response, result)
 }
 
 func (this *Client) PutAlias(index string, names ...string) error {
-	actions := new(AliasActions)
-	actionList := make([]map[string]*AliasItem, 0)
-	for _, name := range names {
-		action := &AliasItem{
-			Index: index,
-			Alias: name,
-		}
-		actionList = append(actionList, map[string]*AliasItem{
-			"add": action,
-		})
-	}
-	actions.Actions = actionList
-
-	body, err := json.Marshal(actions)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest("POST", this.url+"/_aliases", bytes.NewReader(body))
-	if err != nil {
-		return err
-	}
-	req.SetBasicAuth(this.basicAuthUser, this.basicAuthPasswd)
-	req.Header.Set("Content-Type", "application/json")
-
-	response, err := this.client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer response.Body.Close()
-
-	result := new(AliasResult)
-
-	err = json.NewDecoder(response.Body).Decode(result)
+	return this.PutAliasContext(context.Background(), index, names...)
+}
 
-	if err != nil {
-		return err
-	}
-	if result.Error != nil {
-		return errors.New(result.Error.Reason)
+func (this *Client) PutAliasContext(ctx context.Context, index string, names ...string) error {
+	actions := make([]*AliasAction, len(names))
+	for i, name := range names {
+		actions[i] = NewAliasAddAction(name, index)
 	}
-	return nil
+	return this.UpdateAliasesContext(ctx, actions...)
 }
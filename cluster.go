@@ -0,0 +1,101 @@
+package elastic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const (
+	GREEN  = "green"
+	YELLOW = "yellow"
+	RED    = "red"
+)
+
+type ClusterHealthResult struct {
+	ClusterName                 string                  `json:"cluster_name,omitempty"`
+	Status                      string                  `json:"status,omitempty"`
+	TimedOut                    bool                    `json:"timed_out"`
+	NumberOfNodes               int                     `json:"number_of_nodes"`
+	NumberOfDataNodes           int                     `json:"number_of_data_nodes"`
+	ActivePrimaryShards         int                     `json:"active_primary_shards"`
+	ActiveShards                int                     `json:"active_shards"`
+	RelocatingShards            int                     `json:"relocating_shards"`
+	InitializingShards          int                     `json:"initializing_shards"`
+	UnassignedShards            int                     `json:"unassigned_shards"`
+	DelayedUnassignedShards     int                     `json:"delayed_unassigned_shards"`
+	NumberOfPendingTasks        int                     `json:"number_of_pending_tasks"`
+	NumberOfInFlightFetch       int                     `json:"number_of_in_flight_fetch"`
+	TaskMaxWaitingInQueueMillis int64                   `json:"task_max_waiting_in_queue_millis"`
+	ActiveShardsPercentAsNumber float64                 `json:"active_shards_percent_as_number"`
+	Indices                     map[string]*IndexHealth `json:"indices,omitempty"`
+	Error                       *Error                  `json:"error,omitempty"`
+}
+
+type IndexHealth struct {
+	Status              string                  `json:"status,omitempty"`
+	NumberOfShards      int                     `json:"number_of_shards"`
+	NumberOfReplicas    int                     `json:"number_of_replicas"`
+	ActivePrimaryShards int                     `json:"active_primary_shards"`
+	ActiveShards        int                     `json:"active_shards"`
+	RelocatingShards    int                     `json:"relocating_shards"`
+	InitializingShards  int                     `json:"initializing_shards"`
+	UnassignedShards    int                     `json:"unassigned_shards"`
+	Shards              map[string]*ShardHealth `json:"shards,omitempty"`
+}
+
+type ShardHealth struct {
+	Status             string `json:"status,omitempty"`
+	PrimaryActive      bool   `json:"primary_active"`
+	ActiveShards       int    `json:"active_shards"`
+	RelocatingShards   int    `json:"relocating_shards"`
+	InitializingShards int    `json:"initializing_shards"`
+	UnassignedShards   int    `json:"unassigned_shards"`
+}
+
+// get the health of cluster,index can be empty for the whole cluster or many indices joined by comma
+// params can be level=cluster|indices|shards,wait_for_status,wait_for_no_relocating_shards,
+// wait_for_no_initializing_shards,wait_for_active_shards,wait_for_nodes,wait_for_events,timeout,local
+// if the waiting is timeout,the result with TimedOut true and a 408 *ElasticError are returned
+func (this *Client) ClusterHealth(index string, params ...string) (*ClusterHealthResult, error) {
+	return this.ClusterHealthContext(context.Background(), index, params...)
+}
+
+func (this *Client) ClusterHealthContext(ctx context.Context, index string, params ...string) (*ClusterHealthResult, error) {
+	response, err := this.performRequest(ctx, "GET", buildPath(params, "_cluster", "health", index), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	result := new(ClusterHealthResult)
+	err = this.decodeResponse(ctx, response, result)
+	if err != nil {
+		if IsStatusCode(err, http.StatusRequestTimeout) {
+			return result, err
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// wait until the cluster or index is at least status,status can be GREEN or YELLOW,
+// timeout is like "30s",index can be empty for the whole cluster
+// the http timeout set by SetTimeOut must be longer than timeout
+func (this *Client) WaitForStatus(index string, status string, timeout string) (*ClusterHealthResult, error) {
+	return this.WaitForStatusContext(context.Background(), index, status, timeout)
+}
+
+func (this *Client) WaitForStatusContext(ctx context.Context, index string, status string, timeout string) (*ClusterHealthResult, error) {
+	params := []string{Param("wait_for_status", status)}
+	if timeout != "" {
+		params = append(params, Param("timeout", timeout))
+	}
+	result, err := this.ClusterHealthContext(ctx, index, params...)
+	if err != nil {
+		if result != nil && result.TimedOut {
+			// wrap the 408 *ElasticError,so IsStatusCode and AsElasticError still work
+			return result, fmt.Errorf("wait for status %s timeout,current status:%s,%w", status, result.Status, err)
+		}
+		return nil, err
+	}
+	return result, nil
+}
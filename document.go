@@ -1,8 +1,12 @@
 package elastic
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"net/http"
+	"net/url"
+	"strings"
 )
 
 type Action struct {
@@ -39,14 +43,8 @@ func (this Action) Format() ([]byte, error) {
 	if this.Routing != "" {
 		op[this.OpType]["routing"] = this.Routing
 	}
-	if this.Refresh != nil {
-		op[this.OpType]["refresh"] = *this.Refresh
-	}
 	if this.RetryOnConflict != nil {
-		op[this.OpType]["retry_on_conflict"] = *this.Refresh
-	}
-	if this.DocAsUpsert {
-		op[this.OpType]["doc_as_upsert"] = this.DocAsUpsert
+		op[this.OpType]["retry_on_conflict"] = *this.RetryOnConflict
 	}
 
 	opByte, err := json.Marshal(op)
@@ -61,7 +59,11 @@ func (this Action) Format() ([]byte, error) {
 		return nil, errors.New("no data set")
 	}
 	if this.OpType == "update" {
-		dataByte, err := json.Marshal(map[string]map[string]interface{}{"doc": this.Data})
+		doc := map[string]interface{}{"doc": this.Data}
+		if this.DocAsUpsert {
+			doc["doc_as_upsert"] = true
+		}
+		dataByte, err := json.Marshal(doc)
 		if err != nil {
 			return nil, err
 		}
@@ -77,3 +79,402 @@ func (this Action) Format() ([]byte, error) {
 	return append(opByte, dataByte...), nil
 
 }
+
+type GetResult struct {
+	Index       string                 `json:"_index,omitempty"`
+	Type        string                 `json:"_type,omitempty"`
+	Id          string                 `json:"_id,omitempty"`
+	Version     int64                  `json:"_version,omitempty"`
+	SeqNo       int64                  `json:"_seq_no,omitempty"`
+	PrimaryTerm int64                  `json:"_primary_term,omitempty"`
+	Routing     string                 `json:"_routing,omitempty"`
+	Found       bool                   `json:"found"`
+	Source      *json.RawMessage       `json:"_source,omitempty"`
+	Fields      map[string]interface{} `json:"fields,omitempty"`
+	Error       *Error                 `json:"error,omitempty"`
+	Status      int                    `json:"status,omitempty"`
+}
+
+// decode _source into v,v must be a pointer
+func (this *GetResult) Decode(v interface{}) error {
+	if this.Source == nil {
+		return errors.New("no _source in result")
+	}
+	return json.Unmarshal(*this.Source, v)
+}
+
+// the result of index,create,update and delete
+type DocumentResult struct {
+	Index       string     `json:"_index,omitempty"`
+	Type        string     `json:"_type,omitempty"`
+	Id          string     `json:"_id,omitempty"`
+	Version     int64      `json:"_version,omitempty"`
+	Result      string     `json:"result,omitempty"`
+	Shards      *Shards    `json:"_shards,omitempty"`
+	SeqNo       int64      `json:"_seq_no,omitempty"`
+	PrimaryTerm int64      `json:"_primary_term,omitempty"`
+	Get         *GetResult `json:"get,omitempty"`
+	Error       *Error     `json:"error,omitempty"`
+	Status      int        `json:"status,omitempty"`
+}
+
+// the body of update api
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.3/docs-update.html
+type UpdateDoc struct {
+	doc            interface{}
+	docAsUpsert    *bool
+	script         *Script
+	upsert         interface{}
+	scriptedUpsert *bool
+	detectNoop     *bool
+	source         interface{}
+}
+
+func NewUpdateDoc() *UpdateDoc {
+	return &UpdateDoc{}
+}
+
+// the partial document merged into the existing one
+func (this *UpdateDoc) Doc(doc interface{}) *UpdateDoc {
+	this.doc = doc
+	return this
+}
+
+func (this *UpdateDoc) DocAsUpsert(docAsUpsert bool) *UpdateDoc {
+	this.docAsUpsert = &docAsUpsert
+	return this
+}
+
+func (this *UpdateDoc) Script(script *Script) *UpdateDoc {
+	this.script = script
+	return this
+}
+
+// the document indexed when the document doesn't exist
+func (this *UpdateDoc) Upsert(upsert interface{}) *UpdateDoc {
+	this.upsert = upsert
+	return this
+}
+
+func (this *UpdateDoc) ScriptedUpsert(scriptedUpsert bool) *UpdateDoc {
+	this.scriptedUpsert = &scriptedUpsert
+	return this
+}
+
+func (this *UpdateDoc) DetectNoop(detectNoop bool) *UpdateDoc {
+	this.detectNoop = &detectNoop
+	return this
+}
+
+// return the updated _source in result,it can be true,false or fields like ["name","age"]
+func (this *UpdateDoc) Source(source interface{}) *UpdateDoc {
+	this.source = source
+	return this
+}
+
+// return {"doc":{...},"doc_as_upsert":true} or {"script":{...},"upsert":{...}}
+func (this *UpdateDoc) BuildBody() (map[string]interface{}, error) {
+	if this.doc == nil && this.script == nil {
+		return nil, errors.New("update must have doc or script")
+	}
+	body := make(map[string]interface{})
+	if this.doc != nil {
+		body["doc"] = this.doc
+	}
+	if this.docAsUpsert != nil {
+		body["doc_as_upsert"] = *this.docAsUpsert
+	}
+	if this.script != nil {
+		script, err := this.script.BuildBody()
+		if err != nil {
+			return nil, err
+		}
+		body["script"] = script
+	}
+	if this.upsert != nil {
+		body["upsert"] = this.upsert
+	}
+	if this.scriptedUpsert != nil {
+		body["scripted_upsert"] = *this.scriptedUpsert
+	}
+	if this.detectNoop != nil {
+		body["detect_noop"] = *this.detectNoop
+	}
+	if this.source != nil {
+		body["_source"] = this.source
+	}
+	return body, nil
+}
+
+// build /index/docType/id/endpoint?params,the empty parts are skipped
+func buildPath(params []string, parts ...string) string {
+	path := ""
+	for _, part := range parts {
+		if part != "" {
+			path += "/" + strings.Replace(url.PathEscape(part), "%2C", ",", -1)
+		}
+	}
+	if path == "" {
+		path = "/"
+	}
+	if len(params) > 0 {
+		path += "?" + strings.Join(params, "&")
+	}
+	return path
+}
+
+// join many indices or names into one part of path
+func joinNames(names []string) string {
+	return strings.Join(names, ",")
+}
+
+// []byte and json.RawMessage are sent as they are,others are marshaled to json
+func encodeBody(body interface{}) ([]byte, error) {
+	switch b := body.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return b, nil
+	case json.RawMessage:
+		return b, nil
+	case string:
+		return []byte(b), nil
+	}
+	return json.Marshal(body)
+}
+
+// get a document by id
+// params can be routing,preference,realtime,refresh,_source,version,version_type,
+// if the document is not found,the result's Found is false and no error returned
+func (this *Client) Get(index string, docType string, id string, params ...string) (*GetResult, error) {
+	return this.GetContext(context.Background(), index, docType, id, params...)
+}
+
+func (this *Client) GetContext(ctx context.Context, index string, docType string, id string, params ...string) (*GetResult, error) {
+	if index == "" || docType == "" || id == "" {
+		return nil, errors.New("get must have index,doc_type and id")
+	}
+	response, err := this.performRequest(ctx, "GET", buildPath(params, index, docType, id), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	result := new(GetResult)
+	err = this.decodeResponse(ctx, response, result)
+	if err != nil {
+		if e := AsElasticError(err); e != nil && e.Status == 404 && e.Type == "" {
+			return result, nil
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// index a document,if id is empty,elastic will generate one
+// params can be routing,version,version_type,op_type,refresh,timeout,wait_for_active_shards
+func (this *Client) Index(index string, docType string, id string, body interface{}, params ...string) (*DocumentResult, error) {
+	return this.IndexContext(context.Background(), index, docType, id, body, params...)
+}
+
+func (this *Client) IndexContext(ctx context.Context, index string, docType string, id string, body interface{}, params ...string) (*DocumentResult, error) {
+	if index == "" || docType == "" {
+		return nil, errors.New("index must have index and doc_type")
+	}
+	if id == "" {
+		return this.writeDocument(ctx, "POST", buildPath(params, index, docType), body)
+	}
+	return this.writeDocument(ctx, "PUT", buildPath(params, index, docType, id), body)
+}
+
+// index a document only if it doesn't exist,otherwise a conflict error returned
+func (this *Client) Create(index string, docType string, id string, body interface{}, params ...string) (*DocumentResult, error) {
+	return this.CreateContext(context.Background(), index, docType, id, body, params...)
+}
+
+func (this *Client) CreateContext(ctx context.Context, index string, docType string, id string, body interface{}, params ...string) (*DocumentResult, error) {
+	if index == "" || docType == "" || id == "" {
+		return nil, errors.New("create must have index,doc_type and id")
+	}
+	return this.writeDocument(ctx, "PUT", buildPath(params, index, docType, id, "_create"), body)
+}
+
+// update a document by partial doc or script
+// params can be routing,retry_on_conflict,refresh,timeout,wait_for_active_shards,version,version_type
+func (this *Client) Update(index string, docType string, id string, update *UpdateDoc, params ...string) (*DocumentResult, error) {
+	return this.UpdateContext(context.Background(), index, docType, id, update, params...)
+}
+
+func (this *Client) UpdateContext(ctx context.Context, index string, docType string, id string, update *UpdateDoc, params ...string) (*DocumentResult, error) {
+	if index == "" || docType == "" || id == "" {
+		return nil, errors.New("update must have index,doc_type and id")
+	}
+	if update == nil {
+		return nil, errors.New("update must have body")
+	}
+	body, err := update.BuildBody()
+	if err != nil {
+		return nil, err
+	}
+	return this.writeDocument(ctx, "POST", buildPath(params, index, docType, id, "_update"), body)
+}
+
+// delete a document by id,if the document is not found,
+// the result's Result is "not_found" and the error is a 404 *ElasticError
+func (this *Client) Delete(index string, docType string, id string, params ...string) (*DocumentResult, error) {
+	return this.DeleteContext(context.Background(), index, docType, id, params...)
+}
+
+func (this *Client) DeleteContext(ctx context.Context, index string, docType string, id string, params ...string) (*DocumentResult, error) {
+	if index == "" || docType == "" || id == "" {
+		return nil, errors.New("delete must have index,doc_type and id")
+	}
+	return this.writeDocument(ctx, "DELETE", buildPath(params, index, docType, id), nil)
+}
+
+func (this *Client) writeDocument(ctx context.Context, method string, path string, body interface{}) (*DocumentResult, error) {
+	data, err := encodeBody(body)
+	if err != nil {
+		return nil, err
+	}
+	response, err := this.performRequest(ctx, method, path, data, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	result := new(DocumentResult)
+	err = this.decodeResponse(ctx, response, result)
+	if err != nil {
+		if AsElasticError(err) != nil {
+			return result, err
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// the error of this document in multi get,nil if there is no error
+// multi get gives no status for the items,so it is 404 for index_not_found_exception and 500 for the others
+func (this *GetResult) Err() error {
+	if this.Error == nil {
+		return nil
+	}
+	status := this.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+		if this.Error.Type == "index_not_found_exception" {
+			status = http.StatusNotFound
+		}
+	}
+	return newElasticError(status, this.Error)
+}
+
+// one document to get in multi get
+type MultiGetItem struct {
+	index        string
+	docType      string
+	id           string
+	routing      string
+	source       map[string][]string
+	fetchSource  *bool
+	storedFields []string
+}
+
+func NewMultiGetItem(index string, docType string, id string) *MultiGetItem {
+	return &MultiGetItem{index: index, docType: docType, id: id}
+}
+
+func (this *MultiGetItem) Routing(routing string) *MultiGetItem {
+	this.routing = routing
+	return this
+}
+
+// set return fields,key must includes or excludes
+// if key not in includes or excludes,it will be set includes
+func (this *MultiGetItem) Source(key string, fields ...string) *MultiGetItem {
+	if key != "includes" && key != "excludes" {
+		key = "includes"
+	}
+	for _, field := range fields {
+		if this.source == nil {
+			this.source = make(map[string][]string)
+		}
+		this.source[key] = append(this.source[key], field)
+	}
+	return this
+}
+
+// set false to not return _source
+func (this *MultiGetItem) FetchSource(fetchSource bool) *MultiGetItem {
+	this.fetchSource = &fetchSource
+	return this
+}
+
+func (this *MultiGetItem) StoredFields(fields ...string) *MultiGetItem {
+	this.storedFields = append(this.storedFields, fields...)
+	return this
+}
+
+// return {"_index":"x","_type":"_doc","_id":"1","routing":"r","_source":{"includes":["a"]}}
+func (this *MultiGetItem) BuildBody() (map[string]interface{}, error) {
+	if this.id == "" {
+		return nil, errors.New("multi get item must have id")
+	}
+	item := map[string]interface{}{"_id": this.id}
+	if this.index != "" {
+		item["_index"] = this.index
+	}
+	if this.docType != "" {
+		item["_type"] = this.docType
+	}
+	if this.routing != "" {
+		item["routing"] = this.routing
+	}
+	if this.fetchSource != nil && !*this.fetchSource {
+		item["_source"] = false
+	} else if this.source != nil {
+		item["_source"] = this.source
+	}
+	if len(this.storedFields) > 0 {
+		item["stored_fields"] = this.storedFields
+	}
+	return item, nil
+}
+
+type MultiGetResult struct {
+	Docs   []*GetResult `json:"docs,omitempty"`
+	Error  *Error       `json:"error,omitempty"`
+	Status int          `json:"status,omitempty"`
+}
+
+// get many documents in one request,the docs of result are in the order of items
+// every doc has its own Found and Error,a failed doc doesn't fail the others
+// params can be preference,realtime,refresh,routing,_source
+func (this *Client) MultiGet(items []*MultiGetItem, params ...string) (*MultiGetResult, error) {
+	return this.MultiGetContext(context.Background(), items, params...)
+}
+
+func (this *Client) MultiGetContext(ctx context.Context, items []*MultiGetItem, params ...string) (*MultiGetResult, error) {
+	if len(items) == 0 {
+		return nil, errors.New("multi get must have items")
+	}
+	docs := make([]map[string]interface{}, len(items))
+	for i, item := range items {
+		doc, err := item.BuildBody()
+		if err != nil {
+			return nil, err
+		}
+		docs[i] = doc
+	}
+	body, err := json.Marshal(map[string]interface{}{"docs": docs})
+	if err != nil {
+		return nil, err
+	}
+	response, err := this.performIdempotentRequest(ctx, "POST", buildPath(params, "_mget"), body, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	result := new(MultiGetResult)
+	err = this.decodeResponse(ctx, response, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
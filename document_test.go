@@ -0,0 +1,67 @@
+package elastic
+
+import (
+	"fmt"
+	"log"
+	"testing"
+)
+
+func TestDocument(t *testing.T) {
+	//从连接池中取得一个连接
+	v, err := MyPool.GetClient()
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	defer MyPool.PutClient(v)
+	if v == nil {
+		log.Fatalf("\n\n\n\n*******\n\n\n")
+	}
+
+	client := v.(*Client)
+	index := "test_elastic_document"
+
+	_, err = client.Index(index, "_doc", "1", map[string]interface{}{"name": "a", "count": 1}, Param("refresh", "true"))
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	_, err = client.Create(index, "_doc", "1", map[string]interface{}{"name": "b"})
+	if !IsConflict(err) {
+		log.Fatalf("create an existed document must be conflict,got:%v", err)
+	}
+
+	update := NewUpdateDoc().Script(NewScript().Source("ctx._source.count += params.step").Params("step", 2))
+	updateResult, err := client.Update(index, "_doc", "1", update, Param("retry_on_conflict", 3))
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	fmt.Printf("%s %d\n", updateResult.Result, updateResult.Version)
+
+	getResult, err := client.Get(index, "_doc", "1")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	doc := struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}{}
+	if err := getResult.Decode(&doc); err != nil {
+		log.Fatalf(err.Error())
+	}
+	if doc.Count != 3 {
+		t.Errorf("count must be 3,got:%d", doc.Count)
+	}
+
+	_, err = client.Delete(index, "_doc", "1")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	getResult, err = client.Get(index, "_doc", "1")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	if getResult.Found {
+		t.Errorf("document must be deleted")
+	}
+}
@@ -0,0 +1,90 @@
+package elastic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// returned when elastic response a failure status,
+// it keeps the http status and the error detail elastic gives
+type ElasticError struct {
+	Status    int
+	Type      string
+	Reason    string
+	RootCause RootCause
+	CausedBy  *CausedBy
+	Details   *Error
+}
+
+func (this *ElasticError) Error() string {
+	if this.Type != "" {
+		return fmt.Sprintf("elastic fail,status:%d,type:%s,reason:%s", this.Status, this.Type, this.Reason)
+	}
+	if this.Reason != "" {
+		return fmt.Sprintf("elastic fail,status:%d,reason:%s", this.Status, this.Reason)
+	}
+	return fmt.Sprintf("elastic fail,status:%d,reason:%s", this.Status, http.StatusText(this.Status))
+}
+
+func newElasticError(status int, detail *Error) *ElasticError {
+	err := &ElasticError{Status: status, Details: detail}
+	if detail != nil {
+		err.Type = detail.Type
+		err.Reason = detail.Reason
+		err.RootCause = detail.RootCause
+		if detail.CausedBy.Type != "" || detail.CausedBy.Reason != "" {
+			causedBy := detail.CausedBy
+			err.CausedBy = &causedBy
+		}
+	}
+	return err
+}
+
+// parse the error from a failure response body,
+// if the body is not an elastic error,only the status will be kept
+func createElasticError(status int, body []byte) *ElasticError {
+	resp := struct {
+		Error  *Error `json:"error,omitempty"`
+		Status int    `json:"status,omitempty"`
+	}{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return newElasticError(status, nil)
+		}
+	}
+	return newElasticError(status, resp.Error)
+}
+
+// get the *ElasticError from err,return nil if err is not
+func AsElasticError(err error) *ElasticError {
+	var e *ElasticError
+	if errors.As(err, &e) {
+		return e
+	}
+	return nil
+}
+
+// check if err is an *ElasticError with the given status
+func IsStatusCode(err error, status int) bool {
+	e := AsElasticError(err)
+	return e != nil && e.Status == status
+}
+
+func IsNotFound(err error) bool {
+	return IsStatusCode(err, http.StatusNotFound)
+}
+
+func IsConflict(err error) bool {
+	return IsStatusCode(err, http.StatusConflict)
+}
+
+func IsTooManyRequests(err error) bool {
+	return IsStatusCode(err, http.StatusTooManyRequests)
+}
+
+func IsIndexNotFound(err error) bool {
+	e := AsElasticError(err)
+	return e != nil && e.Type == "index_not_found_exception"
+}
@@ -10,14 +10,22 @@ import (
 var Pool pool.Pool
 
 type EsConfig struct {
-	Host     string `json:"host,omitempty"`
-	Port     int    `json:"port,omitempty"`
-	User     string `json:"user,omitempty"`
-	Passwd   string `json:"passwd,omitempty"`
-	Count    int    `json:"count,omitempty"`
-	MaxCount int    `json:"max_count,omitempty"`
-	MinCount int    `json:"min_count,omitempty"`
-	TimeOut  int    `json:"timeout,omitempty"`
+	Host       string `json:"host,omitempty"`
+	Port       int    `json:"port,omitempty"`
+	User       string `json:"user,omitempty"`
+	Passwd     string `json:"passwd,omitempty"`
+	Count      int    `json:"count,omitempty"`
+	MaxCount   int    `json:"max_count,omitempty"`
+	MinCount   int    `json:"min_count,omitempty"`
+	TimeOut    int    `json:"timeout,omitempty"`
+	MaxRetries int    `json:"max_retries,omitempty"`
+	// many nodes like ["http://10.0.0.1:9200","http://10.0.0.2:9200"],Host and Port are ignored if it is set
+	Hosts               []string `json:"hosts,omitempty"`
+	NodeSelector        string   `json:"node_selector,omitempty"`
+	HealthcheckInterval int      `json:"healthcheck_interval,omitempty"`
+	// find the nodes of cluster by _nodes/http,don't set it when elastic is behind a load balancer
+	Sniff           bool `json:"sniff,omitempty"`
+	SnifferInterval int  `json:"sniffer_interval,omitempty"`
 }
 
 // init Client pool for whole project use
@@ -27,11 +35,31 @@ type EsConfig struct {
 func InitClientPool(esConfig EsConfig) pool.Pool {
 	//factory 创建连接的方法
 	factory := func() (interface{}, error) {
-		client, err := NewClient(
-			SetUrl(fmt.Sprintf("%s:%d", esConfig.Host, esConfig.Port)),
+		options := []ClientOptionFunc{
 			SetBasicAuth(esConfig.User, esConfig.Passwd),
 			SetTimeOut(esConfig.TimeOut),
-		)
+		}
+		if len(esConfig.Hosts) > 0 {
+			options = append(options, SetUrls(esConfig.Hosts...))
+		} else {
+			options = append(options, SetUrl(fmt.Sprintf("%s:%d", esConfig.Host, esConfig.Port)))
+		}
+		if esConfig.NodeSelector != "" {
+			options = append(options, SetNodeSelector(esConfig.NodeSelector))
+		}
+		if esConfig.HealthcheckInterval > 0 {
+			options = append(options, SetHealthcheckInterval(esConfig.HealthcheckInterval))
+		}
+		if esConfig.MaxRetries > 0 {
+			options = append(options, SetRetrier(NewRetrier().MaxRetries(esConfig.MaxRetries)))
+		}
+		if esConfig.Sniff {
+			options = append(options, SetSniff(true), SetSnifferFilter(SkipMasterOnly))
+			if esConfig.SnifferInterval > 0 {
+				options = append(options, SetSnifferInterval(esConfig.SnifferInterval))
+			}
+		}
+		client, err := NewClient(options...)
 		return client, err
 	}
 
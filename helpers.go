@@ -1,9 +1,12 @@
 package elastic
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
+	"net/url"
+	"strings"
 )
 
 const (
@@ -11,6 +14,21 @@ const (
 	DEFAULT_SCROLL_SIZE = "1000"
 )
 
+// build a "key=value" url param used by the params of Client's methods,the value is escaped
+func Param(key string, value interface{}) string {
+	return fmt.Sprintf("%s=%s", key, url.QueryEscape(fmt.Sprint(value)))
+}
+
+// check if the params built by Param have the key
+func hasParam(params []string, key string) bool {
+	for _, param := range params {
+		if strings.HasPrefix(param, key+"=") {
+			return true
+		}
+	}
+	return false
+}
+
 type ScrollResp struct {
 	hits     chan *Hit
 	done     bool
@@ -50,6 +68,11 @@ type ClearScrollResp struct {
 
 // https://www.elastic.co/guide/en/elasticsearch/reference/6.3/search-request-scroll.html
 func Scan(client *Client, query *QueryBody, index string, docType string, params map[string]string) (scrollResp *ScrollResp, err error) {
+	return ScanContext(context.Background(), client, query, index, docType, params)
+}
+
+// same as Scan,but when ctx is done the scroll will be cleared and the hits chan will be closed
+func ScanContext(ctx context.Context, client *Client, query *QueryBody, index string, docType string, params map[string]string) (scrollResp *ScrollResp, err error) {
 	if params == nil {
 		params = map[string]string{}
 	}
@@ -82,7 +105,7 @@ func Scan(client *Client, query *QueryBody, index string, docType string, params
 		count += 1
 	}
 
-	resp, err := client.Search(index, docType, query, paramsList...)
+	resp, err := client.SearchContext(ctx, index, docType, query, paramsList...)
 	if err != nil {
 		return nil, err
 	}
@@ -112,14 +135,25 @@ func Scan(client *Client, query *QueryBody, index string, docType string, params
 				scrollResp.closeChan()
 				break
 			}
+			scrollResp.scrollId = resp.ScrollId
 			for _, hit := range resp.Hits.Hits {
-				scrollResp.push(hit)
+				select {
+				case <-ctx.Done():
+					clearScroll(scrollResp.scrollId, client)
+					scrollResp.closeChan()
+					return
+				case scrollResp.hits <- hit:
+				}
 			}
 			scrollParams["scroll_id"] = resp.ScrollId
-			scrollResp.scrollId = resp.ScrollId
 
-			resp, err = client.Scroll(scrollParams)
+			resp, err = client.ScrollContext(ctx, scrollParams)
 			if err != nil {
+				if ctx.Err() != nil {
+					clearScroll(scrollResp.scrollId, client)
+					scrollResp.closeChan()
+					return
+				}
 				panic(err)
 			}
 
@@ -0,0 +1,171 @@
+package elastic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+type AcknowledgedResult struct {
+	Acknowledged       bool   `json:"acknowledged"`
+	ShardsAcknowledged bool   `json:"shards_acknowledged,omitempty"`
+	Error              *Error `json:"error,omitempty"`
+	Status             int    `json:"status,omitempty"`
+}
+
+// the result of refresh,flush,force merge and clear cache
+type ShardsResult struct {
+	Shards *Shards `json:"_shards,omitempty"`
+	Error  *Error  `json:"error,omitempty"`
+	Status int     `json:"status,omitempty"`
+}
+
+type IndexSettings struct {
+	Settings map[string]interface{} `json:"settings,omitempty"`
+}
+
+// index name to its settings
+type SettingsResult map[string]*IndexSettings
+
+// index can be many indices joined by comma or wildcards like "logs-*"
+// params can be timeout,master_timeout,ignore_unavailable,allow_no_indices,expand_wildcards
+func (this *Client) DeleteIndex(index string, params ...string) (*AcknowledgedResult, error) {
+	return this.DeleteIndexContext(context.Background(), index, params...)
+}
+
+func (this *Client) DeleteIndexContext(ctx context.Context, index string, params ...string) (*AcknowledgedResult, error) {
+	if index == "" {
+		return nil, errors.New("delete index must have index")
+	}
+	return this.acknowledged(ctx, "DELETE", buildPath(params, index), nil)
+}
+
+func (this *Client) OpenIndex(index string, params ...string) (*AcknowledgedResult, error) {
+	return this.OpenIndexContext(context.Background(), index, params...)
+}
+
+func (this *Client) OpenIndexContext(ctx context.Context, index string, params ...string) (*AcknowledgedResult, error) {
+	if index == "" {
+		return nil, errors.New("open index must have index")
+	}
+	return this.acknowledged(ctx, "POST", buildPath(params, index, "_open"), nil)
+}
+
+func (this *Client) CloseIndex(index string, params ...string) (*AcknowledgedResult, error) {
+	return this.CloseIndexContext(context.Background(), index, params...)
+}
+
+func (this *Client) CloseIndexContext(ctx context.Context, index string, params ...string) (*AcknowledgedResult, error) {
+	if index == "" {
+		return nil, errors.New("close index must have index")
+	}
+	return this.acknowledged(ctx, "POST", buildPath(params, index, "_close"), nil)
+}
+
+// index can be empty to refresh all indices
+func (this *Client) Refresh(index string, params ...string) (*ShardsResult, error) {
+	return this.RefreshContext(context.Background(), index, params...)
+}
+
+func (this *Client) RefreshContext(ctx context.Context, index string, params ...string) (*ShardsResult, error) {
+	return this.shards(ctx, buildPath(params, index, "_refresh"))
+}
+
+// index can be empty to flush all indices
+// params can be force,wait_if_ongoing
+func (this *Client) Flush(index string, params ...string) (*ShardsResult, error) {
+	return this.FlushContext(context.Background(), index, params...)
+}
+
+func (this *Client) FlushContext(ctx context.Context, index string, params ...string) (*ShardsResult, error) {
+	return this.shards(ctx, buildPath(params, index, "_flush"))
+}
+
+// merge the segments of index,maxNumSegments 0 means let elastic decide
+// params can be flush
+func (this *Client) ForceMerge(index string, maxNumSegments int, onlyExpungeDeletes bool, params ...string) (*ShardsResult, error) {
+	return this.ForceMergeContext(context.Background(), index, maxNumSegments, onlyExpungeDeletes, params...)
+}
+
+func (this *Client) ForceMergeContext(ctx context.Context, index string, maxNumSegments int, onlyExpungeDeletes bool, params ...string) (*ShardsResult, error) {
+	if maxNumSegments > 0 {
+		params = append(params, Param("max_num_segments", maxNumSegments))
+	}
+	if onlyExpungeDeletes {
+		params = append(params, Param("only_expunge_deletes", "true"))
+	}
+	return this.shards(ctx, buildPath(params, index, "_forcemerge"))
+}
+
+// index can be empty to clear all indices
+// params can be query,fielddata,request,fields
+func (this *Client) ClearCache(index string, params ...string) (*ShardsResult, error) {
+	return this.ClearCacheContext(context.Background(), index, params...)
+}
+
+func (this *Client) ClearCacheContext(ctx context.Context, index string, params ...string) (*ShardsResult, error) {
+	return this.shards(ctx, buildPath(params, index, "_cache", "clear"))
+}
+
+// index can be empty to get all indices' settings
+// params can be flat_settings,include_defaults,ignore_unavailable
+func (this *Client) GetSettings(index string, params ...string) (SettingsResult, error) {
+	return this.GetSettingsContext(context.Background(), index, params...)
+}
+
+func (this *Client) GetSettingsContext(ctx context.Context, index string, params ...string) (SettingsResult, error) {
+	response, err := this.performRequest(ctx, "GET", buildPath(params, index, "_settings"), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	result := make(SettingsResult)
+	err = this.decodeResponse(ctx, response, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// update the dynamic settings of index,like
+// {"index":{"refresh_interval":"-1","number_of_replicas":0}} before bulk loads
+// index can be empty to update all indices
+func (this *Client) UpdateSettings(index string, settings map[string]interface{}, params ...string) (*AcknowledgedResult, error) {
+	return this.UpdateSettingsContext(context.Background(), index, settings, params...)
+}
+
+func (this *Client) UpdateSettingsContext(ctx context.Context, index string, settings map[string]interface{}, params ...string) (*AcknowledgedResult, error) {
+	if len(settings) == 0 {
+		return nil, errors.New("update settings must have settings")
+	}
+	body, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+	return this.acknowledged(ctx, "PUT", buildPath(params, index, "_settings"), body)
+}
+
+func (this *Client) acknowledged(ctx context.Context, method string, path string, body []byte) (*AcknowledgedResult, error) {
+	response, err := this.performIdempotentRequest(ctx, method, path, body, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	result := new(AcknowledgedResult)
+	err = this.decodeResponse(ctx, response, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (this *Client) shards(ctx context.Context, path string) (*ShardsResult, error) {
+	response, err := this.performIdempotentRequest(ctx, "POST", path, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	result := new(ShardsResult)
+	err = this.decodeResponse(ctx, response, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
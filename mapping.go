@@ -0,0 +1,234 @@
+package elastic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// the mapping of one field,Properties is for object and nested,Fields is for multi fields
+type FieldMapping struct {
+	Type           string                   `json:"type,omitempty"`
+	Analyzer       string                   `json:"analyzer,omitempty"`
+	SearchAnalyzer string                   `json:"search_analyzer,omitempty"`
+	Normalizer     string                   `json:"normalizer,omitempty"`
+	Format         string                   `json:"format,omitempty"`
+	Index          *bool                    `json:"index,omitempty"`
+	DocValues      *bool                    `json:"doc_values,omitempty"`
+	Store          *bool                    `json:"store,omitempty"`
+	Enabled        *bool                    `json:"enabled,omitempty"`
+	IgnoreAbove    *int                     `json:"ignore_above,omitempty"`
+	Dynamic        interface{}              `json:"dynamic,omitempty"`
+	CopyTo         interface{}              `json:"copy_to,omitempty"`
+	Properties     map[string]*FieldMapping `json:"properties,omitempty"`
+	Fields         map[string]*FieldMapping `json:"fields,omitempty"`
+}
+
+// the mapping of one doc type
+type TypeMapping struct {
+	Dynamic    interface{}              `json:"dynamic,omitempty"`
+	Meta       map[string]interface{}   `json:"_meta,omitempty"`
+	Source     map[string]interface{}   `json:"_source,omitempty"`
+	Routing    map[string]interface{}   `json:"_routing,omitempty"`
+	Properties map[string]*FieldMapping `json:"properties,omitempty"`
+}
+
+type IndexMappings struct {
+	Mappings map[string]*TypeMapping `json:"mappings,omitempty"`
+}
+
+// index name to its mappings
+type MappingResult map[string]*IndexMappings
+
+// get the mapping of docType in index,return nil if not found
+func (this MappingResult) TypeMapping(index string, docType string) *TypeMapping {
+	if mappings, ok := this[index]; ok && mappings != nil {
+		return mappings.Mappings[docType]
+	}
+	return nil
+}
+
+// index and docType can be empty to get all
+func (this *Client) GetMapping(index string, docType string, params ...string) (MappingResult, error) {
+	return this.GetMappingContext(context.Background(), index, docType, params...)
+}
+
+func (this *Client) GetMappingContext(ctx context.Context, index string, docType string, params ...string) (MappingResult, error) {
+	response, err := this.performRequest(ctx, "GET", buildPath(params, index, "_mapping", docType), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	result := make(MappingResult)
+	err = this.decodeResponse(ctx, response, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// add fields to the mapping of docType,body can be a *TypeMapping,[]byte or anything can be marshaled
+// the existing fields can't be changed,use DiffMapping to check it before
+func (this *Client) PutMapping(index string, docType string, body interface{}, params ...string) (*AcknowledgedResult, error) {
+	return this.PutMappingContext(context.Background(), index, docType, body, params...)
+}
+
+func (this *Client) PutMappingContext(ctx context.Context, index string, docType string, body interface{}, params ...string) (*AcknowledgedResult, error) {
+	if index == "" || docType == "" {
+		return nil, errors.New("put mapping must have index and doc_type")
+	}
+	data, err := encodeBody(body)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("put mapping must have body")
+	}
+	return this.acknowledged(ctx, "PUT", buildPath(params, index, "_mapping", docType), data)
+}
+
+type FieldChange struct {
+	// the full path of field like "user.name" or "title.raw"
+	Field string
+	// the changed parameter like "type" or "analyzer"
+	Param       string
+	Old         interface{}
+	New         interface{}
+	NeedReindex bool
+}
+
+func (this *FieldChange) String() string {
+	return fmt.Sprintf("%s %s:%v->%v", this.Field, this.Param, this.Old, this.New)
+}
+
+// the difference from the current mapping to the desired one
+type MappingDiff struct {
+	Added   []string
+	Removed []string
+	Changed []*FieldChange
+}
+
+func (this *MappingDiff) Empty() bool {
+	return len(this.Added) == 0 && len(this.Removed) == 0 && len(this.Changed) == 0
+}
+
+// the added fields can be put into the existing mapping,
+// but a changed type,analyzer,format etc. can only be done by reindexing.
+// the removed fields are kept in the existing mapping and need no reindex
+func (this *MappingDiff) NeedReindex() bool {
+	for _, change := range this.Changed {
+		if change.NeedReindex {
+			return true
+		}
+	}
+	return false
+}
+
+// compare desired with current,current can be nil for a new mapping
+func DiffMapping(desired *TypeMapping, current *TypeMapping) *MappingDiff {
+	diff := &MappingDiff{Added: []string{}, Removed: []string{}, Changed: []*FieldChange{}}
+	var desiredProperties, currentProperties map[string]*FieldMapping
+	if desired != nil {
+		desiredProperties = desired.Properties
+	}
+	if current != nil {
+		currentProperties = current.Properties
+	}
+	diffProperties(diff, "", desiredProperties, currentProperties)
+	return diff
+}
+
+func diffProperties(diff *MappingDiff, prefix string, desired map[string]*FieldMapping, current map[string]*FieldMapping) {
+	for _, name := range sortedFieldNames(desired, current) {
+		field := prefix + name
+		d, inDesired := desired[name]
+		c, inCurrent := current[name]
+		switch {
+		case inDesired && !inCurrent:
+			diff.Added = append(diff.Added, field)
+		case !inDesired && inCurrent:
+			diff.Removed = append(diff.Removed, field)
+		default:
+			diffField(diff, field, d, c)
+		}
+	}
+}
+
+func diffField(diff *MappingDiff, field string, desired *FieldMapping, current *FieldMapping) {
+	if desired == nil {
+		desired = &FieldMapping{}
+	}
+	if current == nil {
+		current = &FieldMapping{}
+	}
+	changed := func(param string, old interface{}, new interface{}, needReindex bool) {
+		diff.Changed = append(diff.Changed, &FieldChange{Field: field, Param: param, Old: old, New: new, NeedReindex: needReindex})
+	}
+	if fieldType(desired) != fieldType(current) {
+		changed("type", fieldType(current), fieldType(desired), true)
+	}
+	if desired.Analyzer != current.Analyzer {
+		changed("analyzer", current.Analyzer, desired.Analyzer, true)
+	}
+	if desired.Normalizer != current.Normalizer {
+		changed("normalizer", current.Normalizer, desired.Normalizer, true)
+	}
+	if desired.Format != current.Format {
+		changed("format", current.Format, desired.Format, true)
+	}
+	if boolValue(desired.Index, true) != boolValue(current.Index, true) {
+		changed("index", boolValue(current.Index, true), boolValue(desired.Index, true), true)
+	}
+	if boolValue(desired.DocValues, true) != boolValue(current.DocValues, true) {
+		changed("doc_values", boolValue(current.DocValues, true), boolValue(desired.DocValues, true), true)
+	}
+	if boolValue(desired.Store, false) != boolValue(current.Store, false) {
+		changed("store", boolValue(current.Store, false), boolValue(desired.Store, false), true)
+	}
+	// search_analyzer and ignore_above can be updated on an existing field
+	if desired.SearchAnalyzer != current.SearchAnalyzer {
+		changed("search_analyzer", current.SearchAnalyzer, desired.SearchAnalyzer, false)
+	}
+	if intValue(desired.IgnoreAbove) != intValue(current.IgnoreAbove) {
+		changed("ignore_above", intValue(current.IgnoreAbove), intValue(desired.IgnoreAbove), false)
+	}
+	diffProperties(diff, field+".", desired.Properties, current.Properties)
+	diffProperties(diff, field+".", desired.Fields, current.Fields)
+}
+
+// elastic omits the type of object fields
+func fieldType(field *FieldMapping) string {
+	if field.Type == "" && field.Properties != nil {
+		return "object"
+	}
+	return field.Type
+}
+
+func boolValue(b *bool, defaultValue bool) bool {
+	if b == nil {
+		return defaultValue
+	}
+	return *b
+}
+
+func intValue(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
+
+func sortedFieldNames(fieldMaps ...map[string]*FieldMapping) []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	for _, fields := range fieldMaps {
+		for name := range fields {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
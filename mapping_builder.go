@@ -0,0 +1,610 @@
+package elastic
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// the body of create index and index template
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.3/indices-create-index.html
+type IndexBody struct {
+	settings *SettingsBody
+	mappings []*Mapping
+	aliases  map[string]Query
+}
+
+func NewIndexBody() *IndexBody {
+	return &IndexBody{}
+}
+
+func (this *IndexBody) Settings(settings *SettingsBody) *IndexBody {
+	this.settings = settings
+	return this
+}
+
+// 6.x index only can have one doc type,but the old indices may have many
+func (this *IndexBody) Mappings(mappings ...*Mapping) *IndexBody {
+	this.mappings = append(this.mappings, mappings...)
+	return this
+}
+
+// add an alias of index,filter can be nil
+func (this *IndexBody) Alias(name string, filter Query) *IndexBody {
+	if this.aliases == nil {
+		this.aliases = make(map[string]Query)
+	}
+	this.aliases[name] = filter
+	return this
+}
+
+// return {"settings":{...},"mappings":{"_doc":{...}},"aliases":{...}}
+func (this *IndexBody) BuildBody() (map[string]interface{}, error) {
+	body := make(map[string]interface{})
+	if this.settings != nil {
+		settings, err := this.settings.BuildBody()
+		if err != nil {
+			return nil, err
+		}
+		body["settings"] = settings
+	}
+	if len(this.mappings) > 0 {
+		mappings := make(map[string]interface{})
+		for _, m := range this.mappings {
+			mapping, err := m.BuildBody()
+			if err != nil {
+				return nil, err
+			}
+			mappings[m.Name()] = mapping[m.Name()]
+		}
+		body["mappings"] = mappings
+	}
+	if len(this.aliases) > 0 {
+		aliases, err := buildAliases(this.aliases)
+		if err != nil {
+			return nil, err
+		}
+		body["aliases"] = aliases
+	}
+	return body, nil
+}
+
+// the body CreateIndex expects
+func (this *IndexBody) Bytes() ([]byte, error) {
+	body, err := this.BuildBody()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(body)
+}
+
+func buildAliases(aliases map[string]Query) (map[string]interface{}, error) {
+	body := make(map[string]interface{})
+	for name, filter := range aliases {
+		alias := make(map[string]interface{})
+		query, err := buildQueryMap(filter)
+		if err != nil {
+			return nil, err
+		}
+		if query != nil {
+			alias["filter"] = query["query"]
+		}
+		body[name] = alias
+	}
+	return body, nil
+}
+
+// the settings of index
+type SettingsBody struct {
+	shards          *int
+	replicas        *int
+	refreshInterval string
+	analysis        *Analysis
+	params          []map[string]interface{}
+}
+
+func NewSettingsBody() *SettingsBody {
+	return &SettingsBody{}
+}
+
+func (this *SettingsBody) Shards(shards int) *SettingsBody {
+	this.shards = &shards
+	return this
+}
+
+func (this *SettingsBody) Replicas(replicas int) *SettingsBody {
+	this.replicas = &replicas
+	return this
+}
+
+// like "1s","30s" or "-1" to disable refresh
+func (this *SettingsBody) RefreshInterval(refreshInterval string) *SettingsBody {
+	this.refreshInterval = refreshInterval
+	return this
+}
+
+func (this *SettingsBody) Analysis(analysis *Analysis) *SettingsBody {
+	this.analysis = analysis
+	return this
+}
+
+// other settings add by this func,like Params("max_result_window", 50000)
+func (this *SettingsBody) Params(key string, value interface{}) *SettingsBody {
+	param := make(map[string]interface{})
+	param[key] = value
+	this.params = append(this.params, param)
+	return this
+}
+
+// return {"number_of_shards":1,"number_of_replicas":1,"refresh_interval":"1s","analysis":{...}}
+func (this *SettingsBody) BuildBody() (map[string]interface{}, error) {
+	settings := make(map[string]interface{})
+	for _, param := range this.params {
+		for k, v := range param {
+			settings[k] = v
+		}
+	}
+	if this.shards != nil {
+		settings["number_of_shards"] = *this.shards
+	}
+	if this.replicas != nil {
+		settings["number_of_replicas"] = *this.replicas
+	}
+	if this.refreshInterval != "" {
+		settings["refresh_interval"] = this.refreshInterval
+	}
+	if this.analysis != nil {
+		analysis, err := this.analysis.BuildBody()
+		if err != nil {
+			return nil, err
+		}
+		settings["analysis"] = analysis
+	}
+	return settings, nil
+}
+
+// the analysis settings of index
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.3/analysis-custom-analyzer.html
+type Analysis struct {
+	analyzers   []*AnalysisComponent
+	tokenizers  []*AnalysisComponent
+	filters     []*AnalysisComponent
+	charFilters []*AnalysisComponent
+	normalizers []*AnalysisComponent
+}
+
+func NewAnalysis() *Analysis {
+	return &Analysis{}
+}
+
+func (this *Analysis) Analyzer(analyzers ...*AnalysisComponent) *Analysis {
+	this.analyzers = append(this.analyzers, analyzers...)
+	return this
+}
+
+func (this *Analysis) Tokenizer(tokenizers ...*AnalysisComponent) *Analysis {
+	this.tokenizers = append(this.tokenizers, tokenizers...)
+	return this
+}
+
+func (this *Analysis) Filter(filters ...*AnalysisComponent) *Analysis {
+	this.filters = append(this.filters, filters...)
+	return this
+}
+
+func (this *Analysis) CharFilter(charFilters ...*AnalysisComponent) *Analysis {
+	this.charFilters = append(this.charFilters, charFilters...)
+	return this
+}
+
+func (this *Analysis) Normalizer(normalizers ...*AnalysisComponent) *Analysis {
+	this.normalizers = append(this.normalizers, normalizers...)
+	return this
+}
+
+// return {"analyzer":{...},"tokenizer":{...},"filter":{...},"char_filter":{...},"normalizer":{...}}
+func (this *Analysis) BuildBody() (map[string]interface{}, error) {
+	analysis := make(map[string]interface{})
+	groups := []struct {
+		key        string
+		components []*AnalysisComponent
+	}{
+		{"analyzer", this.analyzers},
+		{"tokenizer", this.tokenizers},
+		{"filter", this.filters},
+		{"char_filter", this.charFilters},
+		{"normalizer", this.normalizers},
+	}
+	for _, group := range groups {
+		if len(group.components) == 0 {
+			continue
+		}
+		components := make(map[string]interface{})
+		for _, c := range group.components {
+			body, err := c.BuildBody()
+			if err != nil {
+				return nil, err
+			}
+			components[c.Name()] = body[c.Name()]
+		}
+		analysis[group.key] = components
+	}
+	return analysis, nil
+}
+
+// an analyzer,tokenizer,token filter,char filter or normalizer of analysis
+type AnalysisComponent struct {
+	name        string
+	_type       string
+	tokenizer   string
+	filters     []string
+	charFilters []string
+	params      []map[string]interface{}
+}
+
+// a custom analyzer,set its tokenizer,filters and char filters
+func NewAnalyzer(name string) *AnalysisComponent {
+	return &AnalysisComponent{name: name, _type: "custom"}
+}
+
+// a custom normalizer,set its filters and char filters
+func NewNormalizer(name string) *AnalysisComponent {
+	return &AnalysisComponent{name: name, _type: "custom"}
+}
+
+// a tokenizer of _type like "ngram","pattern",set its options by Params
+func NewTokenizer(name string, _type string) *AnalysisComponent {
+	return &AnalysisComponent{name: name, _type: _type}
+}
+
+// a token filter of _type like "synonym","stop",set its options by Params
+func NewTokenFilter(name string, _type string) *AnalysisComponent {
+	return &AnalysisComponent{name: name, _type: _type}
+}
+
+// a char filter of _type like "mapping","html_strip",set its options by Params
+func NewCharFilter(name string, _type string) *AnalysisComponent {
+	return &AnalysisComponent{name: name, _type: _type}
+}
+
+func (this *AnalysisComponent) Name() string {
+	return this.name
+}
+
+// change the type,like "standard" for a built-in analyzer with options
+func (this *AnalysisComponent) Type(_type string) *AnalysisComponent {
+	this._type = _type
+	return this
+}
+
+func (this *AnalysisComponent) Tokenizer(tokenizer string) *AnalysisComponent {
+	this.tokenizer = tokenizer
+	return this
+}
+
+func (this *AnalysisComponent) Filter(filters ...string) *AnalysisComponent {
+	this.filters = append(this.filters, filters...)
+	return this
+}
+
+func (this *AnalysisComponent) CharFilter(charFilters ...string) *AnalysisComponent {
+	this.charFilters = append(this.charFilters, charFilters...)
+	return this
+}
+
+// other options add by this func,like Params("min_gram", 1)
+func (this *AnalysisComponent) Params(key string, value interface{}) *AnalysisComponent {
+	param := make(map[string]interface{})
+	param[key] = value
+	this.params = append(this.params, param)
+	return this
+}
+
+// return {"name":{"type":"custom","tokenizer":"ik_max_word","filter":["lowercase"]}}
+func (this *AnalysisComponent) BuildBody() (map[string]interface{}, error) {
+	if this.name == "" {
+		return nil, errors.New("analysis component must have name")
+	}
+	component := make(map[string]interface{})
+	for _, param := range this.params {
+		for k, v := range param {
+			component[k] = v
+		}
+	}
+	if this._type != "" {
+		component["type"] = this._type
+	}
+	if this.tokenizer != "" {
+		component["tokenizer"] = this.tokenizer
+	}
+	if len(this.filters) > 0 {
+		component["filter"] = this.filters
+	}
+	if len(this.charFilters) > 0 {
+		component["char_filter"] = this.charFilters
+	}
+	return map[string]interface{}{this.name: component}, nil
+}
+
+// the mapping of a doc type
+type Mapping struct {
+	docType    string
+	dynamic    interface{}
+	source     map[string]interface{}
+	meta       map[string]interface{}
+	properties []*Property
+}
+
+func NewMapping(docType string) *Mapping {
+	return &Mapping{docType: docType}
+}
+
+func (this *Mapping) Name() string {
+	return this.docType
+}
+
+// true,false or "strict"
+func (this *Mapping) Dynamic(dynamic interface{}) *Mapping {
+	this.dynamic = dynamic
+	return this
+}
+
+// set false to not store _source
+func (this *Mapping) SourceEnabled(enabled bool) *Mapping {
+	if this.source == nil {
+		this.source = make(map[string]interface{})
+	}
+	this.source["enabled"] = enabled
+	return this
+}
+
+func (this *Mapping) Meta(key string, value interface{}) *Mapping {
+	if this.meta == nil {
+		this.meta = make(map[string]interface{})
+	}
+	this.meta[key] = value
+	return this
+}
+
+func (this *Mapping) Properties(properties ...*Property) *Mapping {
+	this.properties = append(this.properties, properties...)
+	return this
+}
+
+// return {"_doc":{"dynamic":"strict","properties":{...}}}
+func (this *Mapping) BuildBody() (map[string]interface{}, error) {
+	if this.docType == "" {
+		return nil, errors.New("mapping must have doc_type")
+	}
+	mapping := make(map[string]interface{})
+	if this.dynamic != nil {
+		mapping["dynamic"] = this.dynamic
+	}
+	if this.source != nil {
+		mapping["_source"] = this.source
+	}
+	if this.meta != nil {
+		mapping["_meta"] = this.meta
+	}
+	properties, err := buildProperties(this.properties)
+	if err != nil {
+		return nil, err
+	}
+	mapping["properties"] = properties
+	return map[string]interface{}{this.docType: mapping}, nil
+}
+
+// the body PutMapping expects
+func (this *Mapping) Bytes() ([]byte, error) {
+	body, err := this.BuildBody()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(body[this.docType])
+}
+
+// convert to *TypeMapping,so it can be compared with the current mapping by DiffMapping
+func (this *Mapping) TypeMapping() (*TypeMapping, error) {
+	data, err := this.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	mapping := new(TypeMapping)
+	if err := json.Unmarshal(data, mapping); err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+func buildProperties(properties []*Property) (map[string]interface{}, error) {
+	body := make(map[string]interface{})
+	for _, p := range properties {
+		property, err := p.BuildBody()
+		if err != nil {
+			return nil, err
+		}
+		body[p.Name()] = property[p.Name()]
+	}
+	return body, nil
+}
+
+// the mapping of a field
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.3/mapping-types.html
+type Property struct {
+	name       string
+	_type      string
+	properties []*Property
+	fields     []*Property
+	contexts   []map[string]interface{}
+	params     []map[string]interface{}
+}
+
+// a field of any type,set its options by Params
+func NewProperty(name string, _type string) *Property {
+	return &Property{name: name, _type: _type}
+}
+
+func NewKeywordProperty(name string) *Property {
+	return NewProperty(name, "keyword")
+}
+
+func NewTextProperty(name string) *Property {
+	return NewProperty(name, "text")
+}
+
+// formats like "yyyy-MM-dd HH:mm:ss","epoch_millis"
+func NewDateProperty(name string, formats ...string) *Property {
+	return NewProperty(name, "date").Format(formats...)
+}
+
+// _type can be long,integer,short,byte,double,float,half_float,scaled_float
+func NewNumericProperty(name string, _type string) *Property {
+	return NewProperty(name, _type)
+}
+
+func NewBooleanProperty(name string) *Property {
+	return NewProperty(name, "boolean")
+}
+
+// a nested field,add its sub fields by Properties
+func NewNestedProperty(name string) *Property {
+	return NewProperty(name, "nested")
+}
+
+// an object field,add its sub fields by Properties
+func NewObjectProperty(name string) *Property {
+	return NewProperty(name, "object")
+}
+
+func NewGeoPointProperty(name string) *Property {
+	return NewProperty(name, "geo_point")
+}
+
+// a completion field for completion suggest,add its contexts by Context
+func NewCompletionProperty(name string) *Property {
+	return NewProperty(name, "completion")
+}
+
+func (this *Property) Name() string {
+	return this.name
+}
+
+func (this *Property) Analyzer(analyzer string) *Property {
+	return this.Params("analyzer", analyzer)
+}
+
+func (this *Property) SearchAnalyzer(searchAnalyzer string) *Property {
+	return this.Params("search_analyzer", searchAnalyzer)
+}
+
+func (this *Property) Normalizer(normalizer string) *Property {
+	return this.Params("normalizer", normalizer)
+}
+
+// many formats are joined by "||"
+func (this *Property) Format(formats ...string) *Property {
+	if len(formats) == 0 {
+		return this
+	}
+	return this.Params("format", strings.Join(formats, "||"))
+}
+
+func (this *Property) Index(index bool) *Property {
+	return this.Params("index", index)
+}
+
+func (this *Property) DocValues(docValues bool) *Property {
+	return this.Params("doc_values", docValues)
+}
+
+func (this *Property) Store(store bool) *Property {
+	return this.Params("store", store)
+}
+
+func (this *Property) IgnoreAbove(ignoreAbove int) *Property {
+	return this.Params("ignore_above", ignoreAbove)
+}
+
+func (this *Property) CopyTo(fields ...string) *Property {
+	return this.Params("copy_to", fields)
+}
+
+// for scaled_float
+func (this *Property) ScalingFactor(scalingFactor float64) *Property {
+	return this.Params("scaling_factor", scalingFactor)
+}
+
+// for object
+func (this *Property) Enabled(enabled bool) *Property {
+	return this.Params("enabled", enabled)
+}
+
+// for object and nested,true,false or "strict"
+func (this *Property) Dynamic(dynamic interface{}) *Property {
+	return this.Params("dynamic", dynamic)
+}
+
+// the sub fields of object and nested
+func (this *Property) Properties(properties ...*Property) *Property {
+	this.properties = append(this.properties, properties...)
+	return this
+}
+
+// the multi fields,like a keyword "raw" field of a text field
+func (this *Property) Fields(fields ...*Property) *Property {
+	this.fields = append(this.fields, fields...)
+	return this
+}
+
+// a context of completion,_type is category or geo,path can be empty
+func (this *Property) Context(name string, _type string, path string) *Property {
+	context := map[string]interface{}{"name": name, "type": _type}
+	if path != "" {
+		context["path"] = path
+	}
+	this.contexts = append(this.contexts, context)
+	return this
+}
+
+// other options add by this func,the same key set later wins
+func (this *Property) Params(key string, value interface{}) *Property {
+	param := make(map[string]interface{})
+	param[key] = value
+	this.params = append(this.params, param)
+	return this
+}
+
+// return {"title":{"type":"text","analyzer":"ik_max_word","fields":{"raw":{"type":"keyword"}}}}
+func (this *Property) BuildBody() (map[string]interface{}, error) {
+	if this.name == "" {
+		return nil, errors.New("property must have name")
+	}
+	property := make(map[string]interface{})
+	for _, param := range this.params {
+		for k, v := range param {
+			property[k] = v
+		}
+	}
+	// elastic omits the type of object
+	if this._type != "" && !(this._type == "object" && len(this.properties) > 0) {
+		property["type"] = this._type
+	}
+	if len(this.properties) > 0 {
+		properties, err := buildProperties(this.properties)
+		if err != nil {
+			return nil, err
+		}
+		property["properties"] = properties
+	}
+	if len(this.fields) > 0 {
+		fields, err := buildProperties(this.fields)
+		if err != nil {
+			return nil, err
+		}
+		property["fields"] = fields
+	}
+	if len(this.contexts) > 0 {
+		property["contexts"] = this.contexts
+	}
+	return map[string]interface{}{this.name: property}, nil
+}
@@ -0,0 +1,124 @@
+package elastic
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDiffMapping(t *testing.T) {
+	current := new(TypeMapping)
+	err := json.Unmarshal([]byte(`{"properties":{
+		"title":{"type":"text","analyzer":"standard","fields":{"raw":{"type":"keyword"}}},
+		"user":{"properties":{"name":{"type":"keyword"}}},
+		"time":{"type":"date","format":"yyyy-MM-dd"},
+		"old":{"type":"keyword"}
+	}}`), current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	desired := new(TypeMapping)
+	err = json.Unmarshal([]byte(`{"properties":{
+		"title":{"type":"text","analyzer":"ik_max_word","fields":{"raw":{"type":"keyword"}}},
+		"user":{"properties":{"name":{"type":"keyword"},"age":{"type":"integer"}}},
+		"time":{"type":"date","format":"yyyy-MM-dd"},
+		"count":{"type":"long"}
+	}}`), desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := DiffMapping(desired, current)
+	if len(diff.Added) != 2 || diff.Added[0] != "count" || diff.Added[1] != "user.age" {
+		t.Errorf("added must be [count user.age],got:%v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0] != "old" {
+		t.Errorf("removed must be [old],got:%v", diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].String() != "title analyzer:standard->ik_max_word" {
+		t.Errorf("changed must be title's analyzer,got:%v", diff.Changed)
+	}
+	if !diff.NeedReindex() {
+		t.Errorf("change analyzer must need reindex")
+	}
+}
+
+func TestIndexBody(t *testing.T) {
+	analysis := NewAnalysis().
+		Analyzer(NewAnalyzer("my_analyzer").Tokenizer("my_ngram").Filter("lowercase")).
+		Tokenizer(NewTokenizer("my_ngram", "ngram").Params("min_gram", 1).Params("max_gram", 2))
+	mapping := NewMapping("_doc").Dynamic("strict").Properties(
+		NewKeywordProperty("id"),
+		NewTextProperty("title").Analyzer("my_analyzer").Fields(NewKeywordProperty("raw").IgnoreAbove(256)),
+		NewDateProperty("time", "yyyy-MM-dd HH:mm:ss", "epoch_millis"),
+		NewNestedProperty("tags").Properties(NewKeywordProperty("name"), NewNumericProperty("weight", "float")),
+		NewCompletionProperty("suggest").Context("type", "category", "type"),
+	)
+	body, err := NewIndexBody().Settings(NewSettingsBody().Shards(3).Replicas(1).Analysis(analysis)).Mappings(mapping).Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"mappings":{"_doc":{"dynamic":"strict","properties":{` +
+		`"id":{"type":"keyword"},` +
+		`"suggest":{"contexts":[{"name":"type","path":"type","type":"category"}],"type":"completion"},` +
+		`"tags":{"properties":{"name":{"type":"keyword"},"weight":{"type":"float"}},"type":"nested"},` +
+		`"time":{"format":"yyyy-MM-dd HH:mm:ss||epoch_millis","type":"date"},` +
+		`"title":{"analyzer":"my_analyzer","fields":{"raw":{"ignore_above":256,"type":"keyword"}},"type":"text"}}}},` +
+		`"settings":{"analysis":{"analyzer":{"my_analyzer":{"filter":["lowercase"],"tokenizer":"my_ngram","type":"custom"}},` +
+		`"tokenizer":{"my_ngram":{"max_gram":2,"min_gram":1,"type":"ngram"}}},"number_of_replicas":1,"number_of_shards":3}}`
+	if string(body) != expected {
+		t.Errorf("index body is wrong,got:%s", body)
+	}
+
+	desired, err := mapping.TypeMapping()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := DiffMapping(desired, desired); !diff.Empty() {
+		t.Errorf("the same mapping must have no diff,got:%v", diff)
+	}
+}
+
+type testTag struct {
+	Name   string  `json:"name"`
+	Weight float32 `json:"weight"`
+}
+
+type testBase struct {
+	Id string `json:"id" es:"keyword"`
+}
+
+type testDoc struct {
+	testBase
+	Title    string            `json:"title" es:"text,analyzer=ik_max_word,index=true"`
+	Tags     []testTag         `json:"tags" es:"nested"`
+	Time     string            `json:"time" es:"date,format=yyyy-MM-dd HH:mm:ss"`
+	Created  *time.Time        `json:"created"`
+	Count    int64             `json:"count"`
+	Extra    map[string]string `json:"extra"`
+	Ignored  string            `json:"ignored" es:"-"`
+	Skipped  string            `json:"-"`
+	internal string
+}
+
+func TestNewMappingFromStruct(t *testing.T) {
+	mapping, err := NewMappingFromStruct("_doc", &testDoc{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := mapping.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"properties":{` +
+		`"count":{"type":"long"},` +
+		`"created":{"type":"date"},` +
+		`"extra":{"type":"object"},` +
+		`"id":{"type":"keyword"},` +
+		`"tags":{"properties":{"name":{"type":"keyword"},"weight":{"type":"float"}},"type":"nested"},` +
+		`"time":{"format":"yyyy-MM-dd HH:mm:ss","type":"date"},` +
+		`"title":{"analyzer":"ik_max_word","index":true,"type":"text"}}}`
+	if string(body) != expected {
+		t.Errorf("mapping is wrong,got:%s", body)
+	}
+}
@@ -3,6 +3,7 @@ package elastic
 import (
 	"encoding/json"
 	"log"
+	"net/http"
 	"strings"
 )
 
@@ -20,6 +21,14 @@ type SearchResult struct {
 	ScrollId     string               `json:"_scroll_id,omitempty"`
 }
 
+// the error of this search in multi search,nil if there is no error
+func (this *SearchResult) Err() error {
+	if this.Error == nil {
+		return nil
+	}
+	return newElasticError(this.Status, this.Error)
+}
+
 type Suggest struct {
 	Text    string    `json:"text,omitempty"`
 	Offset  int       `json:"offset,omitempty"`
@@ -37,10 +46,11 @@ type Option struct {
 }
 
 type Shards struct {
-	Total      int `json:"total,omitempty"`
-	Successful int `json:"successful,omitempty"`
-	Skipped    int `json:"skipped,omitempty"`
-	Failed     int `json:"failed,omitempty"`
+	Total      int                      `json:"total,omitempty"`
+	Successful int                      `json:"successful,omitempty"`
+	Skipped    int                      `json:"skipped,omitempty"`
+	Failed     int                      `json:"failed,omitempty"`
+	Failures   []map[string]interface{} `json:"failures,omitempty"`
 }
 type Clusters struct {
 	Total      int `json:"total,omitempty"`
@@ -112,9 +122,21 @@ type Error struct {
 	CausedBy  CausedBy  `json:"caused_by,omitempty"`
 }
 
+// some api response the error just as a string,keep it as reason
+func (this *Error) UnmarshalJSON(data []byte) error {
+	var reason string
+	if err := json.Unmarshal(data, &reason); err == nil {
+		this.Reason = reason
+		return nil
+	}
+	type plainError Error
+	return json.Unmarshal(data, (*plainError)(this))
+}
+
 type CausedBy struct {
-	Type   string `json:"type,omitempty"`
-	Reason string `json:"reason,omitempty"`
+	Type     string    `json:"type,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+	CausedBy *CausedBy `json:"caused_by,omitempty"`
 }
 
 type BulkResult struct {
@@ -131,6 +153,30 @@ type BulkItem struct {
 	Update *SubBulkItem `json:"update,omitempty"`
 }
 
+// the result of the item whatever its op type is
+func (this *BulkItem) Result() *SubBulkItem {
+	switch {
+	case this.Index != nil:
+		return this.Index
+	case this.Create != nil:
+		return this.Create
+	case this.Update != nil:
+		return this.Update
+	}
+	return this.Delete
+}
+
+// the failed items of the request,nil if all of them succeed
+func (this *BulkResult) Failed() []*SubBulkItem {
+	var failed []*SubBulkItem
+	for _, item := range this.Items {
+		if r := item.Result(); r != nil && r.Failed() {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
 type SubBulkItem struct {
 	Index       string `json:"_index,omitempty"`
 	DocType     string `json:"_type,omitempty"`
@@ -144,6 +190,14 @@ type SubBulkItem struct {
 	Error       Error  `json:"error,omitempty"`
 }
 
+// a delete of missing document is 404 without error,it is not a failure
+func (this *SubBulkItem) Failed() bool {
+	if this.Status >= 200 && this.Status <= 299 {
+		return false
+	}
+	return this.Status != http.StatusNotFound || this.Error.Type != ""
+}
+
 type CreateIndexResult struct {
 	Took               int    `json:"took,omitempty"`
 	Error              *Error `json:"error,omitempty"`
@@ -0,0 +1,192 @@
+package elastic
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ROUND_ROBIN = "round_robin"
+	RANDOM      = "random"
+
+	DEFAULT_HEALTHCHECK_INTERVAL = 60 * time.Second
+	DEFAULT_HEALTHCHECK_TIMEOUT  = 5 * time.Second
+)
+
+var ErrNoNode = errors.New("no elastic node set")
+
+// the health of one elastic node the client sends requests to
+type NodeHealth struct {
+	Url       string
+	Dead      bool
+	Failures  int
+	DeadSince time.Time
+}
+
+type node struct {
+	url       string
+	dead      bool
+	failures  int
+	deadSince time.Time
+}
+
+// keep the nodes and pick one for every request
+// dead nodes are skipped until the health check finds them alive again
+type nodeList struct {
+	mu       sync.Mutex
+	nodes    []*node
+	next     int
+	selector string
+	rand     *rand.Rand
+}
+
+func newNodeList(urls []string, selector string) *nodeList {
+	list := &nodeList{selector: selector, rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	list.set(urls)
+	return list
+}
+
+// replace the nodes by urls,the health of the nodes already known is kept
+func (this *nodeList) set(urls []string) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	old := make(map[string]*node)
+	for _, n := range this.nodes {
+		old[n.url] = n
+	}
+	nodes := make([]*node, 0, len(urls))
+	for _, url := range urls {
+		url = strings.TrimRight(url, "/")
+		if url == "" {
+			continue
+		}
+		if n, ok := old[url]; ok {
+			nodes = append(nodes, n)
+		} else {
+			nodes = append(nodes, &node{url: url})
+		}
+	}
+	this.nodes = nodes
+}
+
+// pick an alive node by the selector,if all nodes are dead,
+// the one dead for the longest time is tried
+func (this *nodeList) pick() (*node, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	if len(this.nodes) == 0 {
+		return nil, ErrNoNode
+	}
+	alive := make([]*node, 0, len(this.nodes))
+	for _, n := range this.nodes {
+		if !n.dead {
+			alive = append(alive, n)
+		}
+	}
+	if len(alive) == 0 {
+		oldest := this.nodes[0]
+		for _, n := range this.nodes[1:] {
+			if n.deadSince.Before(oldest.deadSince) {
+				oldest = n
+			}
+		}
+		return oldest, nil
+	}
+	if this.selector == RANDOM {
+		return alive[this.rand.Intn(len(alive))], nil
+	}
+	n := alive[this.next%len(alive)]
+	this.next = (this.next + 1) % len(alive)
+	return n, nil
+}
+
+func (this *nodeList) markDead(n *node) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	if !n.dead {
+		n.dead = true
+		n.deadSince = time.Now()
+	}
+	n.failures += 1
+}
+
+func (this *nodeList) markAlive(n *node) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	n.dead = false
+	n.failures = 0
+	n.deadSince = time.Time{}
+}
+
+func (this *nodeList) deadNodes() []*node {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	nodes := make([]*node, 0)
+	for _, n := range this.nodes {
+		if n.dead {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+func (this *nodeList) health() []NodeHealth {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	health := make([]NodeHealth, len(this.nodes))
+	for i, n := range this.nodes {
+		health[i] = NodeHealth{Url: n.url, Dead: n.dead, Failures: n.failures, DeadSince: n.deadSince}
+	}
+	return health
+}
+
+// get the health of every node
+func (this *Client) Nodes() []NodeHealth {
+	return this.nodes.health()
+}
+
+// ping the dead nodes,the alive ones will be used again
+func (this *Client) Healthcheck() {
+	for _, n := range this.nodes.deadNodes() {
+		ctx, cancel := context.WithTimeout(context.Background(), DEFAULT_HEALTHCHECK_TIMEOUT)
+		resp, err := this.sendRequest(ctx, "GET", n.url, nil, "")
+		cancel()
+		if err != nil {
+			continue
+		}
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode == 200 {
+			this.nodes.markAlive(n)
+		}
+	}
+}
+
+// a single node is always tried even if it is dead,so the check is only
+// started for many nodes,sniffing or an interval set by SetHealthcheckInterval
+func (this *Client) startHealthcheck() {
+	if this.healthcheckInterval <= 0 {
+		return
+	}
+	if len(this.urls) <= 1 && !this.sniff && !this.healthcheckSet {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(this.healthcheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-this.stop:
+				return
+			case <-ticker.C:
+				this.Healthcheck()
+			}
+		}
+	}()
+}
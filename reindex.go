@@ -0,0 +1,300 @@
+package elastic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// the body of reindex api
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.3/docs-reindex.html
+type Reindex struct {
+	sourceIndex  []string
+	sourceType   []string
+	query        Query
+	batchSize    *int
+	sourceFields []string
+	sort         []map[string]interface{}
+	destIndex    string
+	destType     string
+	opType       string
+	versionType  string
+	pipeline     string
+	routing      string
+	script       *Script
+	conflicts    string
+	size         *int
+}
+
+func NewReindex() *Reindex {
+	return &Reindex{}
+}
+
+// the indices to copy from
+func (this *Reindex) Source(indices ...string) *Reindex {
+	this.sourceIndex = append(this.sourceIndex, indices...)
+	return this
+}
+
+func (this *Reindex) SourceType(docTypes ...string) *Reindex {
+	this.sourceType = append(this.sourceType, docTypes...)
+	return this
+}
+
+// only copy the documents match query,if query is a *QueryBody,only its query part is used
+func (this *Reindex) Query(query Query) *Reindex {
+	this.query = query
+	return this
+}
+
+// the scroll batch size of the source
+func (this *Reindex) BatchSize(batchSize int) *Reindex {
+	this.batchSize = &batchSize
+	return this
+}
+
+// only copy these fields of _source
+func (this *Reindex) SourceFields(fields ...string) *Reindex {
+	this.sourceFields = append(this.sourceFields, fields...)
+	return this
+}
+
+func (this *Reindex) Sort(sort map[string]interface{}) *Reindex {
+	this.sort = append(this.sort, sort)
+	return this
+}
+
+// the index to copy to
+func (this *Reindex) Dest(index string) *Reindex {
+	this.destIndex = index
+	return this
+}
+
+func (this *Reindex) DestType(docType string) *Reindex {
+	this.destType = docType
+	return this
+}
+
+// set create to only copy the missing documents
+func (this *Reindex) OpType(opType string) *Reindex {
+	this.opType = opType
+	return this
+}
+
+// internal or external
+func (this *Reindex) VersionType(versionType string) *Reindex {
+	this.versionType = versionType
+	return this
+}
+
+func (this *Reindex) Pipeline(pipeline string) *Reindex {
+	this.pipeline = pipeline
+	return this
+}
+
+// keep,discard or =value
+func (this *Reindex) Routing(routing string) *Reindex {
+	this.routing = routing
+	return this
+}
+
+func (this *Reindex) Script(script *Script) *Reindex {
+	this.script = script
+	return this
+}
+
+// set proceed to not abort on version conflicts
+func (this *Reindex) Conflicts(conflicts string) *Reindex {
+	this.conflicts = conflicts
+	return this
+}
+
+// the max documents to copy
+func (this *Reindex) Size(size int) *Reindex {
+	this.size = &size
+	return this
+}
+
+// return {"source":{"index":"a","query":{...}},"dest":{"index":"b"},"script":{...}}
+func (this *Reindex) BuildBody() (map[string]interface{}, error) {
+	if len(this.sourceIndex) == 0 {
+		return nil, errors.New("reindex must have source index")
+	}
+	if this.destIndex == "" {
+		return nil, errors.New("reindex must have dest index")
+	}
+	source := make(map[string]interface{})
+	source["index"] = this.sourceIndex
+	if len(this.sourceType) > 0 {
+		source["type"] = this.sourceType
+	}
+	query, err := buildQueryMap(this.query)
+	if err != nil {
+		return nil, err
+	}
+	if query != nil {
+		source["query"] = query["query"]
+	}
+	if this.batchSize != nil {
+		source["size"] = *this.batchSize
+	}
+	if len(this.sourceFields) > 0 {
+		source["_source"] = this.sourceFields
+	}
+	if len(this.sort) > 0 {
+		source["sort"] = this.sort
+	}
+
+	dest := make(map[string]interface{})
+	dest["index"] = this.destIndex
+	if this.destType != "" {
+		dest["type"] = this.destType
+	}
+	if this.opType != "" {
+		dest["op_type"] = this.opType
+	}
+	if this.versionType != "" {
+		dest["version_type"] = this.versionType
+	}
+	if this.pipeline != "" {
+		dest["pipeline"] = this.pipeline
+	}
+	if this.routing != "" {
+		dest["routing"] = this.routing
+	}
+
+	body := map[string]interface{}{"source": source, "dest": dest}
+	if this.script != nil {
+		script, err := this.script.BuildBody()
+		if err != nil {
+			return nil, err
+		}
+		body["script"] = script
+	}
+	if this.conflicts != "" {
+		body["conflicts"] = this.conflicts
+	}
+	if this.size != nil {
+		body["size"] = *this.size
+	}
+	return body, nil
+}
+
+// copy documents from source to dest
+// params can be refresh,slices,requests_per_second,wait_for_completion,timeout,
+// with wait_for_completion=false only the result's Task is set,poll it by GetTask
+func (this *Client) Reindex(reindex *Reindex, params ...string) (*ByQueryResult, error) {
+	return this.ReindexContext(context.Background(), reindex, params...)
+}
+
+func (this *Client) ReindexContext(ctx context.Context, reindex *Reindex, params ...string) (*ByQueryResult, error) {
+	if reindex == nil {
+		return nil, errors.New("reindex must have body")
+	}
+	b, err := reindex.BuildBody()
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	return this.byQuery(ctx, buildPath(params, "_reindex"), body)
+}
+
+// move alias from oldIndex to a new index without downtime:
+// create newIndex by body,copy the documents of oldIndex into it,check the count of documents
+// and then move alias from oldIndex to newIndex in one _aliases request
+// alias must point to oldIndex,and the writes to oldIndex should be stopped before it
+// reindex can be nil,a copy of it is sent with source and dest set to oldIndex and newIndex,
+// the documents count of newIndex must equal to the count of oldIndex match reindex's types,query and size,
+// so don't use it with scripts which drop documents
+// if any step fails,newIndex is deleted and alias is kept on oldIndex,
+// but if the alias can't be known to be still on oldIndex after a failed swap,newIndex is kept
+func (this *Client) ReindexWithAlias(alias string, oldIndex string, newIndex string, body []byte, reindex *Reindex) (*ByQueryResult, error) {
+	return this.ReindexWithAliasContext(context.Background(), alias, oldIndex, newIndex, body, reindex)
+}
+
+func (this *Client) ReindexWithAliasContext(ctx context.Context, alias string, oldIndex string, newIndex string, body []byte, reindex *Reindex) (*ByQueryResult, error) {
+	if alias == "" || oldIndex == "" || newIndex == "" {
+		return nil, errors.New("reindex with alias must have alias,old index and new index")
+	}
+	// work on a copy,the caller's reindex is kept as it is
+	copied := NewReindex()
+	if reindex != nil {
+		*copied = *reindex
+	}
+	reindex = copied
+	reindex.sourceIndex = []string{oldIndex}
+	reindex.Dest(newIndex)
+
+	if err := this.CreateIndexContext(ctx, newIndex, body); err != nil {
+		return nil, err
+	}
+
+	result, err := this.ReindexContext(ctx, reindex, Param("refresh", "true"))
+	if err == nil && len(result.Failures) > 0 {
+		err = fmt.Errorf("reindex fail,%d failures", len(result.Failures))
+	}
+	if err == nil {
+		err = this.checkReindexCount(ctx, oldIndex, newIndex, reindex)
+	}
+	if err != nil {
+		return result, this.dropReindexed(newIndex, err)
+	}
+
+	err = this.SwapAliasContext(ctx, alias, oldIndex, newIndex)
+	if err == nil {
+		return result, nil
+	}
+	// the swap may be applied even if it fails on the client side,like a timeout or a canceled ctx,
+	// so newIndex is only dropped when the alias is known to be still on oldIndex
+	indices, checkErr := this.AliasIndicesContext(context.Background(), alias)
+	if checkErr == nil {
+		onOld, onNew := false, false
+		for _, index := range indices {
+			onOld = onOld || index == oldIndex
+			onNew = onNew || index == newIndex
+		}
+		if onNew && !onOld {
+			return result, nil
+		}
+		if onOld && !onNew {
+			return result, this.dropReindexed(newIndex, err)
+		}
+	} else if AsElasticError(err) != nil {
+		// elastic rejected the _aliases request,so it is not applied
+		return result, this.dropReindexed(newIndex, err)
+	}
+	return result, fmt.Errorf("%s,alias %s may point to %s or %s,%s is kept", err, alias, oldIndex, newIndex, newIndex)
+}
+
+// delete newIndex after a failed ReindexWithAlias and return err
+func (this *Client) dropReindexed(newIndex string, err error) error {
+	// the rollback should not be canceled with the caller's ctx
+	if _, dropErr := this.DeleteIndexContext(context.Background(), newIndex); dropErr != nil {
+		return fmt.Errorf("%s,and delete %s fail,reason:%s", err, newIndex, dropErr)
+	}
+	return err
+}
+
+// the documents of oldIndex are counted with the same types,query and size as reindex
+func (this *Client) checkReindexCount(ctx context.Context, oldIndex string, newIndex string, reindex *Reindex) error {
+	oldCount, err := this.CountContext(ctx, oldIndex, joinNames(reindex.sourceType), reindex.query)
+	if err != nil {
+		return err
+	}
+	expected := oldCount.Count
+	if reindex.size != nil && int64(*reindex.size) < expected {
+		expected = int64(*reindex.size)
+	}
+	newCount, err := this.CountContext(ctx, newIndex, "", nil)
+	if err != nil {
+		return err
+	}
+	if expected != newCount.Count {
+		return fmt.Errorf("reindex fail,%s has %d documents to copy but %s has %d", oldIndex, expected, newIndex, newCount.Count)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package elastic
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	DEFAULT_MAX_RETRIES     = 3
+	DEFAULT_INITIAL_BACKOFF = 100 * time.Millisecond
+	DEFAULT_MAX_BACKOFF     = 5 * time.Second
+)
+
+// decide if a failed request should be sent again and how long to wait before it
+// status is 0 when no response was received
+type Retrier struct {
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	retryable      func(status int, err error) bool
+	mu             sync.Mutex
+	rand           *rand.Rand
+}
+
+func NewRetrier() *Retrier {
+	return &Retrier{
+		maxRetries:     DEFAULT_MAX_RETRIES,
+		initialBackoff: DEFAULT_INITIAL_BACKOFF,
+		maxBackoff:     DEFAULT_MAX_BACKOFF,
+		retryable:      DefaultRetryable,
+		rand:           rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// the max times to resend a request,the request is sent at most maxRetries+1 times
+func (this *Retrier) MaxRetries(maxRetries int) *Retrier {
+	this.maxRetries = maxRetries
+	return this
+}
+
+// the wait doubles from initial on every retry and never exceeds max
+func (this *Retrier) Backoff(initial time.Duration, max time.Duration) *Retrier {
+	this.initialBackoff = initial
+	this.maxBackoff = max
+	return this
+}
+
+// replace the func which decides what status or error can be retried
+func (this *Retrier) Retryable(retryable func(status int, err error) bool) *Retrier {
+	this.retryable = retryable
+	return this
+}
+
+// check if the retry-th retry is allowed for the status and err
+func (this *Retrier) shouldRetry(retry int, status int, err error) bool {
+	if retry >= this.maxRetries || this.retryable == nil {
+		return false
+	}
+	return this.retryable(status, err)
+}
+
+// exponential backoff with jitter,the wait is random in [d/2,d]
+func (this *Retrier) backoff(retry int) time.Duration {
+	d := this.initialBackoff
+	for i := 0; i < retry && d < this.maxBackoff; i++ {
+		d *= 2
+	}
+	if d > this.maxBackoff {
+		d = this.maxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := int64(d / 2)
+	this.mu.Lock()
+	jitter := this.rand.Int63n(half + 1)
+	this.mu.Unlock()
+	return time.Duration(half + jitter)
+}
+
+// wait the backoff of the retry-th retry,return ctx.Err() if ctx is done first
+func (this *Retrier) wait(ctx context.Context, retry int) error {
+	timer := time.NewTimer(this.backoff(retry))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retry on connection errors and 429,502,503,504
+// the connection errors are only retried for idempotent requests,like GET,HEAD,PUT,DELETE,searches
+// and Bulk with ids for all actions,the others like Index without id,Update and by query
+// are only retried when the node can't be connected,so they won't be applied twice,
+// this applies to the funcs set by Retryable too
+func DefaultRetryable(status int, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// the connection to node can't be made,so the request is not sent
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
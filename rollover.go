@@ -0,0 +1,146 @@
+package elastic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+)
+
+// the body of rollover api,the alias rolls over to a new index when any condition matches
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.3/indices-rollover-index.html
+type Rollover struct {
+	alias    string
+	newIndex string
+	maxAge   string
+	maxDocs  *int64
+	maxSize  string
+	dryRun   bool
+	body     *IndexBody
+}
+
+// alias must point to one index,or have one write index
+func NewRollover(alias string) *Rollover {
+	return &Rollover{alias: alias}
+}
+
+// like "7d","12h"
+func (this *Rollover) MaxAge(maxAge string) *Rollover {
+	this.maxAge = maxAge
+	return this
+}
+
+func (this *Rollover) MaxDocs(maxDocs int64) *Rollover {
+	this.maxDocs = &maxDocs
+	return this
+}
+
+// like "50gb"
+func (this *Rollover) MaxSize(maxSize string) *Rollover {
+	this.maxSize = maxSize
+	return this
+}
+
+// the name of the new index,if empty the number at the end of the old index is increased,
+// like "logs-000001" to "logs-000002"
+func (this *Rollover) NewIndex(newIndex string) *Rollover {
+	this.newIndex = newIndex
+	return this
+}
+
+// only check the conditions,no index is created
+func (this *Rollover) DryRun(dryRun bool) *Rollover {
+	this.dryRun = dryRun
+	return this
+}
+
+// the settings,mappings and aliases of the new index
+func (this *Rollover) Body(body *IndexBody) *Rollover {
+	this.body = body
+	return this
+}
+
+// return {"conditions":{"max_age":"7d","max_docs":1000},"settings":{...}}
+func (this *Rollover) BuildBody() (map[string]interface{}, error) {
+	body := make(map[string]interface{})
+	if this.body != nil {
+		b, err := this.body.BuildBody()
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range b {
+			body[k] = v
+		}
+	}
+	conditions := make(map[string]interface{})
+	if this.maxAge != "" {
+		conditions["max_age"] = this.maxAge
+	}
+	if this.maxDocs != nil {
+		conditions["max_docs"] = *this.maxDocs
+	}
+	if this.maxSize != "" {
+		conditions["max_size"] = this.maxSize
+	}
+	if len(conditions) > 0 {
+		body["conditions"] = conditions
+	}
+	return body, nil
+}
+
+type RolloverResult struct {
+	Acknowledged       bool            `json:"acknowledged"`
+	ShardsAcknowledged bool            `json:"shards_acknowledged"`
+	OldIndex           string          `json:"old_index,omitempty"`
+	NewIndex           string          `json:"new_index,omitempty"`
+	RolledOver         bool            `json:"rolled_over"`
+	DryRun             bool            `json:"dry_run"`
+	Conditions         map[string]bool `json:"conditions,omitempty"`
+	Error              *Error          `json:"error,omitempty"`
+	Status             int             `json:"status,omitempty"`
+}
+
+// the matched conditions like "[max_docs: 1000]",sorted
+func (this *RolloverResult) MatchedConditions() []string {
+	matched := make([]string, 0)
+	for condition, ok := range this.Conditions {
+		if ok {
+			matched = append(matched, condition)
+		}
+	}
+	sort.Strings(matched)
+	return matched
+}
+
+// roll the alias over to a new index if any condition matches,or always if no condition set
+// params can be timeout,master_timeout,wait_for_active_shards
+func (this *Client) Rollover(rollover *Rollover, params ...string) (*RolloverResult, error) {
+	return this.RolloverContext(context.Background(), rollover, params...)
+}
+
+func (this *Client) RolloverContext(ctx context.Context, rollover *Rollover, params ...string) (*RolloverResult, error) {
+	if rollover == nil || rollover.alias == "" {
+		return nil, errors.New("rollover must have alias")
+	}
+	b, err := rollover.BuildBody()
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	if rollover.dryRun {
+		params = append(params, Param("dry_run", "true"))
+	}
+	response, err := this.performRequest(ctx, "POST", buildPath(params, rollover.alias, "_rollover", rollover.newIndex), body, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	result := new(RolloverResult)
+	err = this.decodeResponse(ctx, response, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
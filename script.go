@@ -64,6 +64,7 @@ func (this *Script) BuildBody() (map[string]interface{}, error) {
 				params[k] = v
 			}
 		}
+		query["params"] = params
 	}
 
 	return query, nil
@@ -0,0 +1,522 @@
+package elastic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	SNAPSHOT_IN_PROGRESS = "IN_PROGRESS"
+	SNAPSHOT_SUCCESS     = "SUCCESS"
+	SNAPSHOT_FAILED      = "FAILED"
+	SNAPSHOT_PARTIAL     = "PARTIAL"
+	// made by a version can't be restored by the cluster
+	SNAPSHOT_INCOMPATIBLE = "INCOMPATIBLE"
+)
+
+type RepositoryInfo struct {
+	Type     string                 `json:"type,omitempty"`
+	Settings map[string]interface{} `json:"settings,omitempty"`
+}
+
+// repository name to its type and settings
+type RepositoryResult map[string]*RepositoryInfo
+
+type VerifyRepositoryResult struct {
+	Nodes map[string]struct {
+		Name string `json:"name,omitempty"`
+	} `json:"nodes,omitempty"`
+	Error  *Error `json:"error,omitempty"`
+	Status int    `json:"status,omitempty"`
+}
+
+type SnapshotInfo struct {
+	Snapshot           string                   `json:"snapshot,omitempty"`
+	Uuid               string                   `json:"uuid,omitempty"`
+	VersionId          int                      `json:"version_id,omitempty"`
+	Version            string                   `json:"version,omitempty"`
+	Indices            []string                 `json:"indices,omitempty"`
+	IncludeGlobalState bool                     `json:"include_global_state"`
+	State              string                   `json:"state,omitempty"`
+	Reason             string                   `json:"reason,omitempty"`
+	StartTime          string                   `json:"start_time,omitempty"`
+	StartTimeInMillis  int64                    `json:"start_time_in_millis,omitempty"`
+	EndTime            string                   `json:"end_time,omitempty"`
+	EndTimeInMillis    int64                    `json:"end_time_in_millis,omitempty"`
+	DurationInMillis   int64                    `json:"duration_in_millis,omitempty"`
+	Failures           []map[string]interface{} `json:"failures,omitempty"`
+	Shards             *Shards                  `json:"shards,omitempty"`
+}
+
+// the result of create snapshot,Snapshot is set only with wait_for_completion=true
+type CreateSnapshotResult struct {
+	Accepted bool          `json:"accepted"`
+	Snapshot *SnapshotInfo `json:"snapshot,omitempty"`
+	Error    *Error        `json:"error,omitempty"`
+	Status   int           `json:"status,omitempty"`
+}
+
+type GetSnapshotsResult struct {
+	Snapshots []*SnapshotInfo `json:"snapshots,omitempty"`
+	Error     *Error          `json:"error,omitempty"`
+	Status    int             `json:"status,omitempty"`
+}
+
+// the result of restore snapshot,Snapshot is set only with wait_for_completion=true
+type RestoreSnapshotResult struct {
+	Accepted bool `json:"accepted"`
+	Snapshot *struct {
+		Snapshot string   `json:"snapshot,omitempty"`
+		Indices  []string `json:"indices,omitempty"`
+		Shards   *Shards  `json:"shards,omitempty"`
+	} `json:"snapshot,omitempty"`
+	Error  *Error `json:"error,omitempty"`
+	Status int    `json:"status,omitempty"`
+}
+
+type SnapshotStatus struct {
+	Snapshot           string                          `json:"snapshot,omitempty"`
+	Repository         string                          `json:"repository,omitempty"`
+	Uuid               string                          `json:"uuid,omitempty"`
+	State              string                          `json:"state,omitempty"`
+	IncludeGlobalState bool                            `json:"include_global_state"`
+	ShardsStats        *SnapshotShardsStats            `json:"shards_stats,omitempty"`
+	Stats              *SnapshotStats                  `json:"stats,omitempty"`
+	Indices            map[string]*SnapshotIndexStatus `json:"indices,omitempty"`
+}
+
+type SnapshotShardsStats struct {
+	Initializing int `json:"initializing"`
+	Started      int `json:"started"`
+	Finalizing   int `json:"finalizing"`
+	Done         int `json:"done"`
+	Failed       int `json:"failed"`
+	Total        int `json:"total"`
+}
+
+type SnapshotStats struct {
+	NumberOfFiles        int   `json:"number_of_files"`
+	ProcessedFiles       int   `json:"processed_files"`
+	TotalSizeInBytes     int64 `json:"total_size_in_bytes"`
+	ProcessedSizeInBytes int64 `json:"processed_size_in_bytes"`
+	StartTimeInMillis    int64 `json:"start_time_in_millis"`
+	TimeInMillis         int64 `json:"time_in_millis"`
+}
+
+type SnapshotIndexStatus struct {
+	ShardsStats *SnapshotShardsStats `json:"shards_stats,omitempty"`
+	Stats       *SnapshotStats       `json:"stats,omitempty"`
+}
+
+type SnapshotStatusResult struct {
+	Snapshots []*SnapshotStatus `json:"snapshots,omitempty"`
+	Error     *Error            `json:"error,omitempty"`
+	Status    int               `json:"status,omitempty"`
+}
+
+// the body of create snapshot
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.3/modules-snapshots.html
+type SnapshotBody struct {
+	indices            []string
+	includeGlobalState *bool
+	ignoreUnavailable  *bool
+	partial            *bool
+}
+
+func NewSnapshotBody() *SnapshotBody {
+	return &SnapshotBody{}
+}
+
+// the indices to back up,default is all indices
+func (this *SnapshotBody) Indices(indices ...string) *SnapshotBody {
+	this.indices = append(this.indices, indices...)
+	return this
+}
+
+func (this *SnapshotBody) IncludeGlobalState(includeGlobalState bool) *SnapshotBody {
+	this.includeGlobalState = &includeGlobalState
+	return this
+}
+
+func (this *SnapshotBody) IgnoreUnavailable(ignoreUnavailable bool) *SnapshotBody {
+	this.ignoreUnavailable = &ignoreUnavailable
+	return this
+}
+
+// allow back up the indices with unavailable primary shards
+func (this *SnapshotBody) Partial(partial bool) *SnapshotBody {
+	this.partial = &partial
+	return this
+}
+
+// return {"indices":"a,b","include_global_state":false}
+func (this *SnapshotBody) BuildBody() (map[string]interface{}, error) {
+	body := make(map[string]interface{})
+	if len(this.indices) > 0 {
+		body["indices"] = joinNames(this.indices)
+	}
+	if this.includeGlobalState != nil {
+		body["include_global_state"] = *this.includeGlobalState
+	}
+	if this.ignoreUnavailable != nil {
+		body["ignore_unavailable"] = *this.ignoreUnavailable
+	}
+	if this.partial != nil {
+		body["partial"] = *this.partial
+	}
+	return body, nil
+}
+
+// the body of restore snapshot
+type RestoreBody struct {
+	indices             []string
+	includeGlobalState  *bool
+	ignoreUnavailable   *bool
+	partial             *bool
+	includeAliases      *bool
+	renamePattern       string
+	renameReplacement   string
+	indexSettings       map[string]interface{}
+	ignoreIndexSettings []string
+}
+
+func NewRestoreBody() *RestoreBody {
+	return &RestoreBody{}
+}
+
+// the indices to restore,default is all indices in snapshot
+func (this *RestoreBody) Indices(indices ...string) *RestoreBody {
+	this.indices = append(this.indices, indices...)
+	return this
+}
+
+func (this *RestoreBody) IncludeGlobalState(includeGlobalState bool) *RestoreBody {
+	this.includeGlobalState = &includeGlobalState
+	return this
+}
+
+func (this *RestoreBody) IgnoreUnavailable(ignoreUnavailable bool) *RestoreBody {
+	this.ignoreUnavailable = &ignoreUnavailable
+	return this
+}
+
+func (this *RestoreBody) Partial(partial bool) *RestoreBody {
+	this.partial = &partial
+	return this
+}
+
+func (this *RestoreBody) IncludeAliases(includeAliases bool) *RestoreBody {
+	this.includeAliases = &includeAliases
+	return this
+}
+
+// restore the indices with new names,like pattern "index_(.+)" and replacement "restored_index_$1"
+func (this *RestoreBody) Rename(pattern string, replacement string) *RestoreBody {
+	this.renamePattern = pattern
+	this.renameReplacement = replacement
+	return this
+}
+
+// override the settings of restored indices,like IndexSettings("index.number_of_replicas", 0)
+func (this *RestoreBody) IndexSettings(key string, value interface{}) *RestoreBody {
+	if this.indexSettings == nil {
+		this.indexSettings = make(map[string]interface{})
+	}
+	this.indexSettings[key] = value
+	return this
+}
+
+// the settings of the indices in snapshot not to restore
+func (this *RestoreBody) IgnoreIndexSettings(settings ...string) *RestoreBody {
+	this.ignoreIndexSettings = append(this.ignoreIndexSettings, settings...)
+	return this
+}
+
+// return {"indices":"a","rename_pattern":"(.+)","rename_replacement":"restored_$1","index_settings":{...}}
+func (this *RestoreBody) BuildBody() (map[string]interface{}, error) {
+	if (this.renamePattern == "") != (this.renameReplacement == "") {
+		return nil, errors.New("restore must have both rename pattern and rename replacement")
+	}
+	body := make(map[string]interface{})
+	if len(this.indices) > 0 {
+		body["indices"] = joinNames(this.indices)
+	}
+	if this.includeGlobalState != nil {
+		body["include_global_state"] = *this.includeGlobalState
+	}
+	if this.ignoreUnavailable != nil {
+		body["ignore_unavailable"] = *this.ignoreUnavailable
+	}
+	if this.partial != nil {
+		body["partial"] = *this.partial
+	}
+	if this.includeAliases != nil {
+		body["include_aliases"] = *this.includeAliases
+	}
+	if this.renamePattern != "" {
+		body["rename_pattern"] = this.renamePattern
+		body["rename_replacement"] = this.renameReplacement
+	}
+	if len(this.indexSettings) > 0 {
+		body["index_settings"] = this.indexSettings
+	}
+	if len(this.ignoreIndexSettings) > 0 {
+		body["ignore_index_settings"] = this.ignoreIndexSettings
+	}
+	return body, nil
+}
+
+// register a repository,repositoryType is like "fs","url","s3"
+// params can be verify,master_timeout,timeout
+func (this *Client) CreateRepository(name string, repositoryType string, settings map[string]interface{}, params ...string) (*AcknowledgedResult, error) {
+	return this.CreateRepositoryContext(context.Background(), name, repositoryType, settings, params...)
+}
+
+func (this *Client) CreateRepositoryContext(ctx context.Context, name string, repositoryType string, settings map[string]interface{}, params ...string) (*AcknowledgedResult, error) {
+	if name == "" || repositoryType == "" {
+		return nil, errors.New("create repository must have name and type")
+	}
+	body, err := json.Marshal(map[string]interface{}{"type": repositoryType, "settings": settings})
+	if err != nil {
+		return nil, err
+	}
+	return this.acknowledged(ctx, "PUT", buildPath(params, "_snapshot", name), body)
+}
+
+// register a shared filesystem repository,location must be in path.repo of every node
+func (this *Client) CreateFsRepository(name string, location string, compress bool, params ...string) (*AcknowledgedResult, error) {
+	return this.CreateFsRepositoryContext(context.Background(), name, location, compress, params...)
+}
+
+func (this *Client) CreateFsRepositoryContext(ctx context.Context, name string, location string, compress bool, params ...string) (*AcknowledgedResult, error) {
+	settings := map[string]interface{}{"location": location, "compress": compress}
+	return this.CreateRepositoryContext(ctx, name, "fs", settings, params...)
+}
+
+// check the repository is usable by all nodes
+func (this *Client) VerifyRepository(name string) (*VerifyRepositoryResult, error) {
+	return this.VerifyRepositoryContext(context.Background(), name)
+}
+
+func (this *Client) VerifyRepositoryContext(ctx context.Context, name string) (*VerifyRepositoryResult, error) {
+	if name == "" {
+		return nil, errors.New("verify repository must have name")
+	}
+	response, err := this.performIdempotentRequest(ctx, "POST", buildPath(nil, "_snapshot", name, "_verify"), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	result := new(VerifyRepositoryResult)
+	err = this.decodeResponse(ctx, response, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// names can be empty to get all repositories
+func (this *Client) GetRepository(names ...string) (RepositoryResult, error) {
+	return this.GetRepositoryContext(context.Background(), names...)
+}
+
+func (this *Client) GetRepositoryContext(ctx context.Context, names ...string) (RepositoryResult, error) {
+	response, err := this.performRequest(ctx, "GET", buildPath(nil, "_snapshot", joinNames(names)), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	result := make(RepositoryResult)
+	err = this.decodeResponse(ctx, response, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (this *Client) DeleteRepository(name string) (*AcknowledgedResult, error) {
+	return this.DeleteRepositoryContext(context.Background(), name)
+}
+
+func (this *Client) DeleteRepositoryContext(ctx context.Context, name string) (*AcknowledgedResult, error) {
+	if name == "" {
+		return nil, errors.New("delete repository must have name")
+	}
+	return this.acknowledged(ctx, "DELETE", buildPath(nil, "_snapshot", name), nil)
+}
+
+// body can be nil to back up all indices
+// params can be wait_for_completion,master_timeout
+func (this *Client) CreateSnapshot(repository string, snapshot string, body *SnapshotBody, params ...string) (*CreateSnapshotResult, error) {
+	return this.CreateSnapshotContext(context.Background(), repository, snapshot, body, params...)
+}
+
+func (this *Client) CreateSnapshotContext(ctx context.Context, repository string, snapshot string, body *SnapshotBody, params ...string) (*CreateSnapshotResult, error) {
+	if repository == "" || snapshot == "" {
+		return nil, errors.New("create snapshot must have repository and snapshot")
+	}
+	var data []byte
+	if body != nil {
+		b, err := body.BuildBody()
+		if err != nil {
+			return nil, err
+		}
+		data, err = json.Marshal(b)
+		if err != nil {
+			return nil, err
+		}
+	}
+	response, err := this.performRequest(ctx, "PUT", buildPath(params, "_snapshot", repository, snapshot), data, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	result := new(CreateSnapshotResult)
+	err = this.decodeResponse(ctx, response, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// snapshots can be empty to get all snapshots in repository,or wildcards
+func (this *Client) GetSnapshots(repository string, snapshots ...string) (*GetSnapshotsResult, error) {
+	return this.GetSnapshotsContext(context.Background(), repository, snapshots...)
+}
+
+func (this *Client) GetSnapshotsContext(ctx context.Context, repository string, snapshots ...string) (*GetSnapshotsResult, error) {
+	if repository == "" {
+		return nil, errors.New("get snapshots must have repository")
+	}
+	names := joinNames(snapshots)
+	if names == "" {
+		names = "_all"
+	}
+	response, err := this.performRequest(ctx, "GET", buildPath(nil, "_snapshot", repository, names), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	result := new(GetSnapshotsResult)
+	err = this.decodeResponse(ctx, response, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// delete a snapshot,or abort it if it is in progress
+func (this *Client) DeleteSnapshot(repository string, snapshot string) (*AcknowledgedResult, error) {
+	return this.DeleteSnapshotContext(context.Background(), repository, snapshot)
+}
+
+func (this *Client) DeleteSnapshotContext(ctx context.Context, repository string, snapshot string) (*AcknowledgedResult, error) {
+	if repository == "" || snapshot == "" {
+		return nil, errors.New("delete snapshot must have repository and snapshot")
+	}
+	return this.acknowledged(ctx, "DELETE", buildPath(nil, "_snapshot", repository, snapshot), nil)
+}
+
+// restore the indices in snapshot,the existing indices must be closed or renamed by body.Rename
+// body can be nil to restore all indices
+// params can be wait_for_completion,master_timeout
+func (this *Client) RestoreSnapshot(repository string, snapshot string, body *RestoreBody, params ...string) (*RestoreSnapshotResult, error) {
+	return this.RestoreSnapshotContext(context.Background(), repository, snapshot, body, params...)
+}
+
+func (this *Client) RestoreSnapshotContext(ctx context.Context, repository string, snapshot string, body *RestoreBody, params ...string) (*RestoreSnapshotResult, error) {
+	if repository == "" || snapshot == "" {
+		return nil, errors.New("restore snapshot must have repository and snapshot")
+	}
+	var data []byte
+	if body != nil {
+		b, err := body.BuildBody()
+		if err != nil {
+			return nil, err
+		}
+		data, err = json.Marshal(b)
+		if err != nil {
+			return nil, err
+		}
+	}
+	response, err := this.performRequest(ctx, "POST", buildPath(params, "_snapshot", repository, snapshot, "_restore"), data, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	result := new(RestoreSnapshotResult)
+	err = this.decodeResponse(ctx, response, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// the detail progress of snapshots,repository and snapshots both can be empty to get the running ones
+func (this *Client) SnapshotStatus(repository string, snapshots ...string) (*SnapshotStatusResult, error) {
+	return this.SnapshotStatusContext(context.Background(), repository, snapshots...)
+}
+
+func (this *Client) SnapshotStatusContext(ctx context.Context, repository string, snapshots ...string) (*SnapshotStatusResult, error) {
+	response, err := this.performRequest(ctx, "GET", buildPath(nil, "_snapshot", repository, joinNames(snapshots), "_status"), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	result := new(SnapshotStatusResult)
+	err = this.decodeResponse(ctx, response, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// poll the snapshot until it is not in progress,the wait between polls doubles up to 30s
+// progress is called with the status after every poll and can be nil
+// only SUCCESS returns no error,for the others like FAILED,PARTIAL and INCOMPATIBLE,
+// the snapshot and an error are returned
+func (this *Client) WaitForSnapshot(repository string, snapshot string, progress func(status *SnapshotStatus)) (*SnapshotInfo, error) {
+	return this.WaitForSnapshotContext(context.Background(), repository, snapshot, progress)
+}
+
+func (this *Client) WaitForSnapshotContext(ctx context.Context, repository string, snapshot string, progress func(status *SnapshotStatus)) (*SnapshotInfo, error) {
+	interval := DEFAULT_TASK_POLL_INTERVAL
+	for {
+		result, err := this.GetSnapshotsContext(ctx, repository, snapshot)
+		if err != nil {
+			return nil, err
+		}
+		if len(result.Snapshots) == 0 {
+			return nil, fmt.Errorf("snapshot %s not found in %s", snapshot, repository)
+		}
+		info := result.Snapshots[0]
+		switch info.State {
+		case SNAPSHOT_IN_PROGRESS:
+		case SNAPSHOT_SUCCESS:
+			return info, nil
+		case SNAPSHOT_FAILED, SNAPSHOT_PARTIAL, SNAPSHOT_INCOMPATIBLE:
+			return info, fmt.Errorf("snapshot %s is %s,reason:%s", snapshot, info.State, info.Reason)
+		default:
+			return info, fmt.Errorf("snapshot %s is in unknown state %q", snapshot, info.State)
+		}
+
+		if progress != nil {
+			status, err := this.SnapshotStatusContext(ctx, repository, snapshot)
+			if err != nil {
+				return nil, err
+			}
+			if len(status.Snapshots) > 0 {
+				progress(status.Snapshots[0])
+			}
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		interval *= 2
+		if interval > DEFAULT_TASK_MAX_POLL_INTERVAL {
+			interval = DEFAULT_TASK_MAX_POLL_INTERVAL
+		}
+	}
+}
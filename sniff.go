@@ -0,0 +1,165 @@
+package elastic
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	DEFAULT_SNIFFER_INTERVAL = 15 * time.Minute
+	DEFAULT_SNIFFER_TIMEOUT  = 5 * time.Second
+)
+
+type NodesInfoResult struct {
+	ClusterName string               `json:"cluster_name,omitempty"`
+	Nodes       map[string]*NodeInfo `json:"nodes,omitempty"`
+	Error       *Error               `json:"error,omitempty"`
+	Status      int                  `json:"status,omitempty"`
+}
+
+type NodeInfo struct {
+	Id         string            `json:"-"`
+	Name       string            `json:"name,omitempty"`
+	Host       string            `json:"host,omitempty"`
+	Ip         string            `json:"ip,omitempty"`
+	Version    string            `json:"version,omitempty"`
+	Roles      []string          `json:"roles,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Http       *NodeHttp         `json:"http,omitempty"`
+}
+
+type NodeHttp struct {
+	BoundAddress   []string `json:"bound_address,omitempty"`
+	PublishAddress string   `json:"publish_address,omitempty"`
+}
+
+func (this *NodeInfo) HasRole(role string) bool {
+	for _, r := range this.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// a node only can be master,it holds no data and does no ingest
+func (this *NodeInfo) IsMasterOnly() bool {
+	return this.HasRole("master") && !this.HasRole("data") && !this.HasRole("ingest")
+}
+
+// a sniffer filter skips the master only nodes
+func SkipMasterOnly(node *NodeInfo) bool {
+	return !node.IsMasterOnly()
+}
+
+// find the nodes of cluster by _nodes/http when the client is created and at every interval
+func SetSniff(sniff bool) ClientOptionFunc {
+	return func(this *Client) {
+		this.sniff = sniff
+	}
+}
+
+// how often to sniff the nodes,0 means only sniff when the client is created
+func SetSnifferInterval(interval int) ClientOptionFunc {
+	return func(this *Client) {
+		this.snifferInterval = time.Duration(interval) * time.Second
+	}
+}
+
+// only the nodes filter returns true will be used
+func SetSnifferFilter(filter func(node *NodeInfo) bool) ClientOptionFunc {
+	return func(this *Client) {
+		this.snifferFilter = filter
+	}
+}
+
+// get the http info of all nodes in cluster
+func (this *Client) NodesInfo() (*NodesInfoResult, error) {
+	return this.NodesInfoContext(context.Background())
+}
+
+func (this *Client) NodesInfoContext(ctx context.Context) (*NodesInfoResult, error) {
+	response, err := this.performRequest(ctx, "GET", "/_nodes/http", nil, "")
+	if err != nil {
+		return nil, err
+	}
+	result := new(NodesInfoResult)
+	err = this.decodeResponse(ctx, response, result)
+	if err != nil {
+		return nil, err
+	}
+	for id, node := range result.Nodes {
+		node.Id = id
+	}
+	return result, nil
+}
+
+// update the nodes by the http address of the nodes in cluster,
+// the nodes are kept if no node is found
+func (this *Client) Sniff() error {
+	return this.SniffContext(context.Background())
+}
+
+func (this *Client) SniffContext(ctx context.Context) error {
+	result, err := this.NodesInfoContext(ctx)
+	if err != nil {
+		return err
+	}
+	scheme := this.scheme()
+	urls := make([]string, 0, len(result.Nodes))
+	for _, node := range result.Nodes {
+		if node.Http == nil || node.Http.PublishAddress == "" {
+			continue
+		}
+		if this.snifferFilter != nil && !this.snifferFilter(node) {
+			continue
+		}
+		urls = append(urls, scheme+"://"+publishAddress(node.Http.PublishAddress))
+	}
+	if len(urls) == 0 {
+		return errors.New("sniff fail,no node found")
+	}
+	this.nodes.set(urls)
+	return nil
+}
+
+// the scheme of sniffed nodes follows the first url set
+func (this *Client) scheme() string {
+	if len(this.urls) > 0 {
+		if u, err := url.Parse(this.urls[0]); err == nil && u.Scheme != "" {
+			return u.Scheme
+		}
+	}
+	return "http"
+}
+
+// publish_address may be "hostname/ip:port" or "ip:port"
+func publishAddress(address string) string {
+	if i := strings.Index(address, "/"); i >= 0 {
+		return address[i+1:]
+	}
+	return address
+}
+
+func (this *Client) startSniffer() {
+	if !this.sniff || this.snifferInterval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(this.snifferInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-this.stop:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), DEFAULT_SNIFFER_TIMEOUT)
+				this.SniffContext(ctx)
+				cancel()
+			}
+		}
+	}()
+}
@@ -0,0 +1,201 @@
+package elastic
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// build the mapping of docType from the struct v by its "es" tags,v can be a struct or a pointer to struct
+//
+//	type Doc struct {
+//		Id      string    `json:"id" es:"keyword"`
+//		Title   string    `json:"title" es:"text,analyzer=ik_max_word,search_analyzer=ik_smart"`
+//		Tags    []Tag     `json:"tags" es:"nested"`
+//		Time    string    `json:"time" es:"date,format=yyyy-MM-dd HH:mm:ss"`
+//		Created time.Time `json:"created"`
+//		Ignored string    `es:"-"`
+//	}
+//
+// the first part of the tag is the type,the others are the options of the field,
+// the field name follows the json tag,the type is guessed when no type set:
+// string is keyword,bool is boolean,numbers are long,integer,short,byte,double or float,
+// time.Time is date,struct is object,map is object without properties,
+// slices and pointers follow their element,embedded structs are flattened like encoding/json
+func NewMappingFromStruct(docType string, v interface{}) (*Mapping, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, errors.New("mapping from struct must have a struct")
+	}
+	properties, err := structProperties(t, map[reflect.Type]bool{})
+	if err != nil {
+		return nil, err
+	}
+	return NewMapping(docType).Properties(properties...), nil
+}
+
+// visiting keeps the structs on the current path to stop the recursive types
+func structProperties(t reflect.Type, visiting map[reflect.Type]bool) ([]*Property, error) {
+	if visiting[t] {
+		return nil, fmt.Errorf("mapping from struct fail,%s is recursive", t)
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	properties := make([]*Property, 0)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("es")
+		if tag == "-" {
+			continue
+		}
+		name, skip := jsonName(field)
+		if skip {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded, err := structProperties(ft, visiting)
+				if err != nil {
+					return nil, err
+				}
+				properties = append(properties, embedded...)
+				continue
+			}
+		}
+		// unexported field
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		property, err := fieldProperty(name, field.Type, tag, visiting)
+		if err != nil {
+			return nil, err
+		}
+		if property != nil {
+			properties = append(properties, property)
+		}
+	}
+	return properties, nil
+}
+
+// return the name in json tag,and if the field is skipped by json:"-"
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	return strings.Split(tag, ",")[0], false
+}
+
+func fieldProperty(name string, t reflect.Type, tag string, visiting map[reflect.Type]bool) (*Property, error) {
+	parts := strings.Split(tag, ",")
+	_type := strings.TrimSpace(parts[0])
+
+	t = elemType(t)
+	if _type == "" {
+		_type = guessType(t)
+		if _type == "" {
+			// the type can't be guessed like interface{},leave it to dynamic mapping
+			return nil, nil
+		}
+	}
+	property := NewProperty(name, _type)
+
+	for _, option := range parts[1:] {
+		kv := strings.SplitN(option, "=", 2)
+		key := strings.TrimSpace(kv[0])
+		if key == "" {
+			continue
+		}
+		if len(kv) == 1 {
+			property.Params(key, true)
+		} else {
+			property.Params(key, optionValue(kv[1]))
+		}
+	}
+
+	if (_type == "object" || _type == "nested") && t.Kind() == reflect.Struct && t != timeType {
+		properties, err := structProperties(t, visiting)
+		if err != nil {
+			return nil, err
+		}
+		property.Properties(properties...)
+	}
+	return property, nil
+}
+
+// the element of pointers,slices and arrays,but []byte is kept for binary
+func elemType(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() {
+		case reflect.Ptr:
+			t = t.Elem()
+		case reflect.Slice, reflect.Array:
+			if t.Elem().Kind() == reflect.Uint8 {
+				return t
+			}
+			t = t.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+func guessType(t reflect.Type) string {
+	if t == timeType {
+		return "date"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "keyword"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return "long"
+	case reflect.Int32, reflect.Uint16:
+		return "integer"
+	case reflect.Int16, reflect.Uint8:
+		return "short"
+	case reflect.Int8:
+		return "byte"
+	case reflect.Float64:
+		return "double"
+	case reflect.Float32:
+		return "float"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "binary"
+	}
+	return ""
+}
+
+// the option values like index=false or ignore_above=256 are sent as bool and number
+func optionValue(value string) interface{} {
+	value = strings.TrimSpace(value)
+	if value == "true" || value == "false" {
+		return value == "true"
+	}
+	if i, err := strconv.Atoi(value); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	return value
+}
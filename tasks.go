@@ -0,0 +1,236 @@
+package elastic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+type TaskResult struct {
+	Completed bool             `json:"completed"`
+	Task      *TaskInfo        `json:"task,omitempty"`
+	Response  *json.RawMessage `json:"response,omitempty"`
+	Error     *Error           `json:"error,omitempty"`
+	Status    int              `json:"status,omitempty"`
+}
+
+type TaskInfo struct {
+	Node               string            `json:"node,omitempty"`
+	Id                 int64             `json:"id,omitempty"`
+	Type               string            `json:"type,omitempty"`
+	Action             string            `json:"action,omitempty"`
+	Status             *TaskStatus       `json:"status,omitempty"`
+	Description        string            `json:"description,omitempty"`
+	StartTimeInMillis  int64             `json:"start_time_in_millis,omitempty"`
+	RunningTimeInNanos int64             `json:"running_time_in_nanos,omitempty"`
+	Cancellable        bool              `json:"cancellable,omitempty"`
+	ParentTaskId       string            `json:"parent_task_id,omitempty"`
+	Headers            map[string]string `json:"headers,omitempty"`
+}
+
+// the progress of reindex,update by query and delete by query
+type TaskStatus struct {
+	Total                int64    `json:"total"`
+	Updated              int64    `json:"updated"`
+	Created              int64    `json:"created"`
+	Deleted              int64    `json:"deleted"`
+	Batches              int64    `json:"batches"`
+	VersionConflicts     int64    `json:"version_conflicts"`
+	Noops                int64    `json:"noops"`
+	Retries              *Retries `json:"retries,omitempty"`
+	ThrottledMillis      int64    `json:"throttled_millis"`
+	RequestsPerSecond    float64  `json:"requests_per_second"`
+	ThrottledUntilMillis int64    `json:"throttled_until_millis"`
+}
+
+type Retries struct {
+	Bulk   int64 `json:"bulk"`
+	Search int64 `json:"search"`
+}
+
+// decode the response of a completed task into v,v must be a pointer
+func (this *TaskResult) Decode(v interface{}) error {
+	if this.Response == nil {
+		return errors.New("no response in task")
+	}
+	return json.Unmarshal(*this.Response, v)
+}
+
+// get a task by id like "oTUltX4IQMOUUVeiohTt8A:12345"
+// params can be wait_for_completion,timeout
+func (this *Client) GetTask(taskId string, params ...string) (*TaskResult, error) {
+	return this.GetTaskContext(context.Background(), taskId, params...)
+}
+
+func (this *Client) GetTaskContext(ctx context.Context, taskId string, params ...string) (*TaskResult, error) {
+	if taskId == "" {
+		return nil, errors.New("get task must have task id")
+	}
+	response, err := this.performRequest(ctx, "GET", buildPath(params, "_tasks", taskId), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	result := new(TaskResult)
+	err = this.decodeResponse(ctx, response, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+const (
+	DEFAULT_TASK_POLL_INTERVAL     = time.Second
+	DEFAULT_TASK_MAX_POLL_INTERVAL = 30 * time.Second
+)
+
+type ListTasksResult struct {
+	Nodes        map[string]*TaskNode     `json:"nodes,omitempty"`
+	NodeFailures []map[string]interface{} `json:"node_failures,omitempty"`
+	TaskFailures []map[string]interface{} `json:"task_failures,omitempty"`
+	Error        *Error                   `json:"error,omitempty"`
+	Status       int                      `json:"status,omitempty"`
+}
+
+type TaskNode struct {
+	Name             string               `json:"name,omitempty"`
+	TransportAddress string               `json:"transport_address,omitempty"`
+	Host             string               `json:"host,omitempty"`
+	Ip               string               `json:"ip,omitempty"`
+	Roles            []string             `json:"roles,omitempty"`
+	Tasks            map[string]*TaskInfo `json:"tasks,omitempty"`
+}
+
+// the tasks of all nodes,sorted by start time
+func (this *ListTasksResult) Tasks() []*TaskInfo {
+	tasks := make([]*TaskInfo, 0)
+	for _, node := range this.Nodes {
+		if node == nil {
+			continue
+		}
+		for _, task := range node.Tasks {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].StartTimeInMillis < tasks[j].StartTimeInMillis
+	})
+	return tasks
+}
+
+// the id used by GetTask and CancelTask,like "oTUltX4IQMOUUVeiohTt8A:12345"
+func (this *TaskInfo) TaskId() string {
+	return fmt.Sprintf("%s:%d", this.Node, this.Id)
+}
+
+// list the running tasks
+// params can be actions like "*reindex,*byquery",detailed,parent_task_id,nodes,wait_for_completion,timeout
+func (this *Client) ListTasks(params ...string) (*ListTasksResult, error) {
+	return this.ListTasksContext(context.Background(), params...)
+}
+
+func (this *Client) ListTasksContext(ctx context.Context, params ...string) (*ListTasksResult, error) {
+	response, err := this.performRequest(ctx, "GET", buildPath(params, "_tasks"), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	result := new(ListTasksResult)
+	err = this.decodeResponse(ctx, response, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// cancel a task by id,taskId can be empty to cancel the tasks match params
+// params can be actions,nodes,parent_task_id
+func (this *Client) CancelTask(taskId string, params ...string) (*ListTasksResult, error) {
+	return this.CancelTaskContext(context.Background(), taskId, params...)
+}
+
+func (this *Client) CancelTaskContext(ctx context.Context, taskId string, params ...string) (*ListTasksResult, error) {
+	if taskId == "" && len(params) == 0 {
+		return nil, errors.New("cancel task must have task id or params")
+	}
+	response, err := this.performIdempotentRequest(ctx, "POST", buildPath(params, "_tasks", taskId, "_cancel"), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	result := new(ListTasksResult)
+	err = this.decodeResponse(ctx, response, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// poll the task until it completes,the wait between polls doubles up to 30s,
+// progress is called with the status of the task after every poll and can be nil
+// if the task fails or completes with failures,the result and an *ElasticError are returned,
+// its status is the status of the first failure or 500
+func (this *Client) WaitForTask(taskId string, progress func(status *TaskStatus)) (*TaskResult, error) {
+	return this.WaitForTaskContext(context.Background(), taskId, progress)
+}
+
+func (this *Client) WaitForTaskContext(ctx context.Context, taskId string, progress func(status *TaskStatus)) (*TaskResult, error) {
+	interval := DEFAULT_TASK_POLL_INTERVAL
+	for {
+		result, err := this.GetTaskContext(ctx, taskId)
+		if err != nil {
+			return nil, err
+		}
+		if progress != nil && result.Task != nil && result.Task.Status != nil {
+			progress(result.Task.Status)
+		}
+		if result.Completed {
+			if result.Error != nil {
+				return result, newElasticError(http.StatusInternalServerError, result.Error)
+			}
+			if result.Response != nil {
+				// reindex and by query complete with the failures in response
+				response := struct {
+					Failures []map[string]interface{} `json:"failures,omitempty"`
+				}{}
+				if err := json.Unmarshal(*result.Response, &response); err == nil && len(response.Failures) > 0 {
+					return result, taskFailureError(response.Failures)
+				}
+			}
+			return result, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		interval *= 2
+		if interval > DEFAULT_TASK_MAX_POLL_INTERVAL {
+			interval = DEFAULT_TASK_MAX_POLL_INTERVAL
+		}
+	}
+}
+
+// build the error of a task from the failures in its response,like the bulk and search failures of reindex,
+// the cause and status are taken from the first failure
+func taskFailureError(failures []map[string]interface{}) *ElasticError {
+	failure := failures[0]
+	status := http.StatusInternalServerError
+	if s, ok := failure["status"].(float64); ok && s > 0 {
+		status = int(s)
+	}
+	detail := new(Error)
+	cause, ok := failure["cause"]
+	if !ok {
+		cause = failure["reason"]
+	}
+	if data, err := json.Marshal(cause); err == nil {
+		json.Unmarshal(data, detail)
+	}
+	detail.Reason = fmt.Sprintf("%d failures,the first:%s", len(failures), detail.Reason)
+	return newElasticError(status, detail)
+}
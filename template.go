@@ -0,0 +1,156 @@
+package elastic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// the body of index template,settings,mappings and aliases are shared with create index by IndexBody
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.3/indices-templates.html
+type IndexTemplate struct {
+	indexPatterns []string
+	order         *int
+	version       *int
+	body          *IndexBody
+}
+
+func NewIndexTemplate(indexPatterns ...string) *IndexTemplate {
+	return &IndexTemplate{indexPatterns: indexPatterns}
+}
+
+func (this *IndexTemplate) IndexPatterns(indexPatterns ...string) *IndexTemplate {
+	this.indexPatterns = append(this.indexPatterns, indexPatterns...)
+	return this
+}
+
+// the templates with higher order are merged later and override the lower ones
+func (this *IndexTemplate) Order(order int) *IndexTemplate {
+	this.order = &order
+	return this
+}
+
+func (this *IndexTemplate) Version(version int) *IndexTemplate {
+	this.version = &version
+	return this
+}
+
+// the settings,mappings and aliases of the created indices
+func (this *IndexTemplate) Body(body *IndexBody) *IndexTemplate {
+	this.body = body
+	return this
+}
+
+// return {"index_patterns":["logs-*"],"order":1,"settings":{...},"mappings":{...},"aliases":{...}}
+func (this *IndexTemplate) BuildBody() (map[string]interface{}, error) {
+	if len(this.indexPatterns) == 0 {
+		return nil, errors.New("index template must have index patterns")
+	}
+	template := make(map[string]interface{})
+	if this.body != nil {
+		body, err := this.body.BuildBody()
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range body {
+			template[k] = v
+		}
+	}
+	template["index_patterns"] = this.indexPatterns
+	if this.order != nil {
+		template["order"] = *this.order
+	}
+	if this.version != nil {
+		template["version"] = *this.version
+	}
+	return template, nil
+}
+
+// the body PutIndexTemplate expects
+func (this *IndexTemplate) Bytes() ([]byte, error) {
+	body, err := this.BuildBody()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(body)
+}
+
+type IndexTemplateInfo struct {
+	Order         int                     `json:"order"`
+	Version       *int                    `json:"version,omitempty"`
+	IndexPatterns []string                `json:"index_patterns,omitempty"`
+	Settings      map[string]interface{}  `json:"settings,omitempty"`
+	Mappings      map[string]*TypeMapping `json:"mappings,omitempty"`
+	Aliases       map[string]*AliasInfo   `json:"aliases,omitempty"`
+}
+
+// template name to its content
+type IndexTemplateResult map[string]*IndexTemplateInfo
+
+// create or replace a template,body can be built by IndexTemplate.Bytes
+// params can be create,order,master_timeout
+func (this *Client) PutIndexTemplate(name string, body []byte, params ...string) (*AcknowledgedResult, error) {
+	return this.PutIndexTemplateContext(context.Background(), name, body, params...)
+}
+
+func (this *Client) PutIndexTemplateContext(ctx context.Context, name string, body []byte, params ...string) (*AcknowledgedResult, error) {
+	if name == "" {
+		return nil, errors.New("put index template must have name")
+	}
+	if len(body) == 0 {
+		return nil, errors.New("put index template must have body")
+	}
+	return this.acknowledged(ctx, "PUT", buildPath(params, "_template", name), body)
+}
+
+// names can be wildcards,empty to get all templates
+// a missing template returns a 404 *ElasticError
+func (this *Client) GetIndexTemplate(names ...string) (IndexTemplateResult, error) {
+	return this.GetIndexTemplateContext(context.Background(), names...)
+}
+
+func (this *Client) GetIndexTemplateContext(ctx context.Context, names ...string) (IndexTemplateResult, error) {
+	response, err := this.performRequest(ctx, "GET", buildPath(nil, "_template", joinNames(names)), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	result := make(IndexTemplateResult)
+	err = this.decodeResponse(ctx, response, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (this *Client) DeleteIndexTemplate(name string, params ...string) (*AcknowledgedResult, error) {
+	return this.DeleteIndexTemplateContext(context.Background(), name, params...)
+}
+
+func (this *Client) DeleteIndexTemplateContext(ctx context.Context, name string, params ...string) (*AcknowledgedResult, error) {
+	if name == "" {
+		return nil, errors.New("delete index template must have name")
+	}
+	return this.acknowledged(ctx, "DELETE", buildPath(params, "_template", name), nil)
+}
+
+func (this *Client) IndexTemplateExists(name string) (bool, error) {
+	return this.IndexTemplateExistsContext(context.Background(), name)
+}
+
+func (this *Client) IndexTemplateExistsContext(ctx context.Context, name string) (bool, error) {
+	if name == "" {
+		return false, errors.New("index template exists must have name")
+	}
+	response, err := this.performRequest(ctx, "HEAD", buildPath(nil, "_template", name), nil, "")
+	if err != nil {
+		return false, err
+	}
+	err = this.decodeResponse(ctx, response, nil)
+	if err != nil {
+		if IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
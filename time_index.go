@@ -0,0 +1,195 @@
+package elastic
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	hourUnit = iota
+	dayUnit
+	monthUnit
+	yearUnit
+)
+
+// the layout tokens of units,only the zero padded ones are supported
+var unitTokens = map[int]string{
+	hourUnit:  "15",
+	dayUnit:   "02",
+	monthUnit: "01",
+	yearUnit:  "2006",
+}
+
+// name the time based indices like "events-2026.10.18"
+// the names for writing and the patterns for searching are both built from a prefix and a go time layout
+type TimeIndex struct {
+	prefix   string
+	layout   string
+	location *time.Location
+}
+
+// layout is a go time layout like "2006.01.02","2006.01" or "2006.01.02.15"
+func NewTimeIndex(prefix string, layout string) *TimeIndex {
+	return &TimeIndex{prefix: prefix, layout: layout, location: time.Local}
+}
+
+// the time zone the names are in,default is time.Local
+func (this *TimeIndex) Location(location *time.Location) *TimeIndex {
+	this.location = location
+	return this
+}
+
+// the index name of t,use it as the index of writing,like Action.Index
+func (this *TimeIndex) Index(t time.Time) string {
+	return this.prefix + t.In(this.location).Format(this.layout)
+}
+
+// all the index names in [from,to],from and to are swapped if from is after to
+func (this *TimeIndex) Indices(from time.Time, to time.Time) []string {
+	if from.After(to) {
+		from, to = to, from
+	}
+	indices := make([]string, 0)
+	unit := this.unit()
+	for t := this.truncate(from, unit); !t.After(to); t = this.next(t, unit) {
+		// the repeated hour of daylight saving time has the same name
+		if index := this.Index(t); len(indices) == 0 || indices[len(indices)-1] != index {
+			indices = append(indices, index)
+		}
+	}
+	return indices
+}
+
+// the indices in [from,to] for searching joined by comma,
+// the whole months or years are replaced by wildcards like "events-2026.10.*" to keep it short
+// from and to are swapped if from is after to,so it is never empty
+func (this *TimeIndex) SearchIndex(from time.Time, to time.Time) string {
+	if from.After(to) {
+		from, to = to, from
+	}
+	patterns := make([]string, 0)
+	unit := this.unit()
+	cuts := this.cuts()
+	for t := this.truncate(from, unit); !t.After(to); {
+		level := unit
+		for l := yearUnit; l > unit; l-- {
+			if _, ok := cuts[l]; !ok {
+				continue
+			}
+			if this.truncate(t, l).Equal(t) && !this.next(t, l).After(this.next(to, unit)) {
+				level = l
+				break
+			}
+		}
+		pattern := this.Index(t)
+		if level != unit {
+			pattern = this.prefix + t.In(this.location).Format(cuts[level]) + "*"
+		}
+		if len(patterns) == 0 || patterns[len(patterns)-1] != pattern {
+			patterns = append(patterns, pattern)
+		}
+		t = this.next(t, level)
+	}
+	return strings.Join(patterns, ",")
+}
+
+// only the existing indices in [from,to],so the missing days won't cause index_not_found_exception
+// the indices of prefix are listed by one _cat/indices request
+func (this *TimeIndex) ExistingIndices(client *Client, from time.Time, to time.Time) ([]string, error) {
+	return this.ExistingIndicesContext(context.Background(), client, from, to)
+}
+
+func (this *TimeIndex) ExistingIndicesContext(ctx context.Context, client *Client, from time.Time, to time.Time) ([]string, error) {
+	if client == nil {
+		return nil, errors.New("existing indices must have client")
+	}
+	cats, err := client.CatIndicesContext(ctx, this.prefix+"*", Param("h", "index"))
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]bool, len(cats))
+	for _, cat := range cats {
+		found[cat.Index] = true
+	}
+	existing := make([]string, 0)
+	for _, index := range this.Indices(from, to) {
+		if found[index] {
+			existing = append(existing, index)
+		}
+	}
+	return existing, nil
+}
+
+// the finest unit in layout
+func (this *TimeIndex) unit() int {
+	for unit := hourUnit; unit < yearUnit; unit++ {
+		if strings.Contains(this.layout, unitTokens[unit]) {
+			return unit
+		}
+	}
+	return yearUnit
+}
+
+// the layouts cut before the finer unit's token,for the units can be replaced by wildcard,
+// like "2006.01." for month and "2006." for year of "2006.01.02",
+// it needs the coarser tokens all before the finer ones
+func (this *TimeIndex) cuts() map[int]string {
+	cuts := make(map[int]string)
+	finest := this.unit()
+	last := -1
+	positions := make(map[int]int)
+	for unit := yearUnit; unit >= finest; unit-- {
+		position := strings.Index(this.layout, unitTokens[unit])
+		if position < 0 {
+			continue
+		}
+		if position < last {
+			return cuts
+		}
+		last = position
+		positions[unit] = position
+	}
+	for unit := finest + 1; unit <= yearUnit; unit++ {
+		finer := -1
+		for u := unit - 1; u >= finest; u-- {
+			if position, ok := positions[u]; ok {
+				finer = position
+				break
+			}
+		}
+		if _, ok := positions[unit]; ok && finer > 0 {
+			cuts[unit] = this.layout[:finer]
+		}
+	}
+	return cuts
+}
+
+func (this *TimeIndex) truncate(t time.Time, unit int) time.Time {
+	t = t.In(this.location)
+	switch unit {
+	case hourUnit:
+		// on absolute time,time.Date resolves the repeated hour of daylight saving time to the first one
+		return t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	case dayUnit:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, this.location)
+	case monthUnit:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, this.location)
+	}
+	return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, this.location)
+}
+
+// the start of the next unit after t,t must be truncated
+func (this *TimeIndex) next(t time.Time, unit int) time.Time {
+	t = this.truncate(t, unit)
+	switch unit {
+	case hourUnit:
+		return t.Add(time.Hour)
+	case dayUnit:
+		return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, this.location)
+	case monthUnit:
+		return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, this.location)
+	}
+	return time.Date(t.Year()+1, 1, 1, 0, 0, 0, 0, this.location)
+}
@@ -0,0 +1,67 @@
+package elastic
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeIndex(t *testing.T) {
+	location := time.FixedZone("CST", 8*3600)
+	timeIndex := NewTimeIndex("events-", "2006.01.02").Location(location)
+
+	if index := timeIndex.Index(time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC)); index != "events-2026.10.18" {
+		t.Errorf("index must be events-2026.10.18,got:%s", index)
+	}
+
+	from := time.Date(2026, 9, 29, 10, 0, 0, 0, location)
+	to := time.Date(2026, 11, 2, 1, 0, 0, 0, location)
+	indices := timeIndex.Indices(from, to)
+	if len(indices) != 35 || indices[0] != "events-2026.09.29" || indices[34] != "events-2026.11.02" {
+		t.Errorf("indices are wrong,got:%v", indices)
+	}
+
+	expected := "events-2026.09.29,events-2026.09.30,events-2026.10.*,events-2026.11.01,events-2026.11.02"
+	if search := timeIndex.SearchIndex(from, to); search != expected {
+		t.Errorf("search index must be %s,got:%s", expected, search)
+	}
+
+	from = time.Date(2025, 12, 31, 0, 0, 0, 0, location)
+	to = time.Date(2027, 1, 1, 0, 0, 0, 0, location)
+	expected = "events-2025.12.31,events-2026.*,events-2027.01.01"
+	if search := timeIndex.SearchIndex(from, to); search != expected {
+		t.Errorf("search index must be %s,got:%s", expected, search)
+	}
+
+	if search := timeIndex.SearchIndex(to, from); search != expected {
+		t.Errorf("search index with from after to must be %s,got:%s", expected, search)
+	}
+
+	monthly := NewTimeIndex("logs-", "2006.01").Location(location)
+	search := monthly.SearchIndex(time.Date(2026, 1, 5, 0, 0, 0, 0, location), time.Date(2026, 12, 1, 0, 0, 0, 0, location))
+	if search != "logs-2026.*" {
+		t.Errorf("monthly search index must be logs-2026.*,got:%s", search)
+	}
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err.Error())
+	}
+	hourly := NewTimeIndex("events-", "2006.01.02.15").Location(newYork)
+	from = time.Date(2026, 11, 1, 0, 0, 0, 0, newYork)
+	to = time.Date(2026, 11, 1, 4, 0, 0, 0, newYork)
+	indices = hourly.Indices(from, to)
+	if len(indices) != 5 || indices[1] != "events-2026.11.01.01" || indices[2] != "events-2026.11.01.02" {
+		t.Errorf("hourly indices over daylight saving time end are wrong,got:%v", indices)
+	}
+	expected = "events-2026.11.01.00,events-2026.11.01.01,events-2026.11.01.02,events-2026.11.01.03,events-2026.11.01.04"
+	if search := hourly.SearchIndex(from, to); search != expected {
+		t.Errorf("search index must be %s,got:%s", expected, search)
+	}
+
+	from = time.Date(2026, 3, 8, 0, 0, 0, 0, newYork)
+	to = time.Date(2026, 3, 8, 4, 0, 0, 0, newYork)
+	indices = hourly.Indices(from, to)
+	if len(indices) != 4 || indices[2] != "events-2026.03.08.03" {
+		t.Errorf("hourly indices over daylight saving time start are wrong,got:%v", indices)
+	}
+}